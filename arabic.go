@@ -0,0 +1,87 @@
+package sanitize
+
+// Arabic letters are romanized following a simplified ALA-LC system without diacritics.
+// As short vowels are rarely written, most words are transliterated as consonants only.
+// Applications may override these with RegisterTransliterations.
+var arabicLetters = TransliterationMap{
+	'ء': "",
+	'آ': "a",
+	'أ': "a",
+	'ؤ': "",
+	'إ': "i",
+	'ئ': "",
+	'ا': "a",
+	'ب': "b",
+	'ة': "a",
+	'ت': "t",
+	'ث': "th",
+	'ج': "j",
+	'ح': "h",
+	'خ': "kh",
+	'د': "d",
+	'ذ': "dh",
+	'ر': "r",
+	'ز': "z",
+	'س': "s",
+	'ش': "sh",
+	'ص': "s",
+	'ض': "d",
+	'ط': "t",
+	'ظ': "z",
+	'ع': "a",
+	'غ': "gh",
+	'ف': "f",
+	'ق': "q",
+	'ك': "k",
+	'ل': "l",
+	'م': "m",
+	'ن': "n",
+	'ه': "h",
+	'و': "w",
+	'ى': "a",
+	'ي': "y",
+
+	// Short vowels and other marks
+	'ً': "an",
+	'ٌ': "un",
+	'ٍ': "in",
+	'َ': "a",
+	'ُ': "u",
+	'ِ': "i",
+	'ّ': "",
+	'ْ': "",
+	'ـ': "",
+
+	// Persian and urdu letters
+	'پ': "p",
+	'چ': "ch",
+	'ژ': "zh",
+	'ک': "k",
+	'گ': "g",
+	'ی': "y",
+
+	// Punctuation and digits
+	'،': ",",
+	'؛': ";",
+	'؟': "?",
+	'٠': "0",
+	'١': "1",
+	'٢': "2",
+	'٣': "3",
+	'٤': "4",
+	'٥': "5",
+	'٦': "6",
+	'٧': "7",
+	'٨': "8",
+	'٩': "9",
+	'۰': "0",
+	'۱': "1",
+	'۲': "2",
+	'۳': "3",
+	'۴': "4",
+	'۵': "5",
+	'۶': "6",
+	'۷': "7",
+	'۸': "8",
+	'۹': "9",
+}
@@ -0,0 +1,30 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var arabicHebrewTests = []Test{
+	{"مرحبا بالعالم", `mrhba-balaalm`},
+	{"كِتَاب", `kitaab`},
+	{"سلام ٢٠٢٤", `slam-2024`},
+	{"شالوم עולם", `shalwm-avlm`},
+	{"תל אביב", `tl-abyb`},
+	{"שָׁלוֹם", `shalvom`},
+}
+
+func TestArabicHebrew(t *testing.T) {
+	for _, test := range arabicHebrewTests {
+		output := Path(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	defer func() { registeredTransliterators = nil }()
+	RegisterTransliterations(map[rune]string{'ו': "o"})
+	output := Path("שלום")
+	if output != "shlom" {
+		t.Fatalf(Format, "שלום", "shlom", output)
+	}
+}
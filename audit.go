@@ -0,0 +1,92 @@
+package sanitize
+
+import (
+	parser "golang.org/x/net/html"
+)
+
+// AuditEvent is a script element, event handler or script url removed by a policy, passed to Policy.Audit.
+type AuditEvent struct {
+	Removal
+
+	// Snippet is the html the removal was found in, the start tag for attributes,
+	// or the element with its content for script elements.
+	Snippet string
+
+	// Offset is the byte offset of the snippet in the input, after a byte order mark,
+	// invalid utf-8 and NUL bytes are removed and line endings normalised if the policy requires.
+	Offset int
+}
+
+// auditor passes the removals recorded in report for which Removal.Script is true to audit,
+// with the html of the token they were found in. Methods do nothing if the auditor is nil.
+type auditor struct {
+	audit  func(AuditEvent)
+	report *Report
+
+	// The number of removals already checked
+	checked int
+
+	// The raw html, type and offset of the current token, and the offset of the next
+	raw       string
+	tokenType parser.TokenType
+	offset    int
+	next      int
+
+	// A script element being removed, which is passed to audit once its end tag is found
+	script *AuditEvent
+}
+
+// read copies the raw html of the current token, it must be called before the token is read.
+func (a *auditor) read(z *parser.Tokenizer) {
+	if a == nil {
+		return
+	}
+	a.check()
+	a.raw = string(z.Raw())
+	a.offset = a.next
+	a.next += len(a.raw)
+}
+
+// token adds the current token to a script element being removed, passing it to audit at its end tag.
+func (a *auditor) token(t parser.Token) {
+	if a == nil {
+		return
+	}
+	a.tokenType = t.Type
+	if a.script == nil {
+		return
+	}
+	a.script.Snippet += a.raw
+	if t.Type == parser.EndTagToken && t.Data == "script" {
+		a.audit(*a.script)
+		a.script = nil
+	}
+}
+
+// check passes the removals recorded for the current token to audit.
+func (a *auditor) check() {
+	for ; a.checked < len(a.report.Removed); a.checked++ {
+		r := a.report.Removed[a.checked]
+		if !r.Script() {
+			continue
+		}
+		event := AuditEvent{Removal: r, Snippet: a.raw, Offset: a.offset}
+		if r.Attribute == "" && a.tokenType == parser.StartTagToken {
+			a.script = &event
+		} else {
+			a.audit(event)
+		}
+	}
+}
+
+// finish passes any removals not yet checked to audit, including a script element without an end tag.
+func (a *auditor) finish() {
+	if a == nil {
+		return
+	}
+	a.check()
+	if a.script != nil {
+		a.audit(*a.script)
+		a.script = nil
+	}
+}
@@ -0,0 +1,62 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	input := `<p title="javascript:x" onclick="a()">text</p><script>alert(1)</script><i>i</i><a href="&#x6a;avascript:b()">link</a><img src="/a.png"><script>d()`
+	expected := []AuditEvent{
+		{Removal{Tag: "p", Attribute: "title", Value: "javascript:x"}, `<p title="javascript:x" onclick="a()">`, 0},
+		{Removal{Tag: "p", Attribute: "onclick", Value: "a()"}, `<p title="javascript:x" onclick="a()">`, 0},
+		{Removal{Tag: "script"}, `<script>alert(1)</script>`, 46},
+		{Removal{Tag: "a", Attribute: "href", Value: "javascript:b()", URL: true}, `<a href="&#x6a;avascript:b()">`, 79},
+		{Removal{Tag: "script"}, `<script>d()`, 135},
+	}
+
+	var events []AuditEvent
+	output, err := Sanitize(input, WithAudit(func(e AuditEvent) { events = append(events, e) }))
+	if err != nil || output != `<p>text</p><i>i</i><a>link</a><img src="/a.png">` {
+		t.Fatalf(Format, input, `<p>text</p><i>i</i><a>link</a><img src="/a.png">`, output)
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Audit events %v, want %v", events, expected)
+	}
+	for i, e := range events {
+		if e != expected[i] {
+			t.Fatalf("Audit event %d %+v, want %+v", i, e, expected[i])
+		}
+		if input[e.Offset:e.Offset+len(e.Snippet)] != e.Snippet {
+			t.Fatalf("Audit event %d offset %d does not match snippet %q", i, e.Offset, e.Snippet)
+		}
+	}
+}
+
+func TestAuditRemovedElements(t *testing.T) {
+	input := `<svg onload=alert(1)><details ontoggle="b()">d</details><object data="javascript:c()"><embed src=x onerror=e() /><script>f()</script></object><p>text</p>`
+	expected := []AuditEvent{
+		{Removal{Tag: "svg", Attribute: "onload", Value: "alert(1)"}, `<svg onload=alert(1)>`, 0},
+		{Removal{Tag: "details", Attribute: "ontoggle", Value: "b()"}, `<details ontoggle="b()">`, 21},
+		{Removal{Tag: "object", Attribute: "data", Value: "javascript:c()", URL: true}, `<object data="javascript:c()">`, 56},
+		{Removal{Tag: "embed", Attribute: "onerror", Value: "e()"}, `<embed src=x onerror=e() />`, 86},
+		{Removal{Tag: "script"}, `<script>f()</script>`, 113},
+	}
+
+	var events []AuditEvent
+	p := &Policy{Tags: []string{"p"}, Audit: func(e AuditEvent) { events = append(events, e) }}
+	output, err := p.Sanitize(input)
+	if err != nil || output != `d<p>text</p>` {
+		t.Fatalf(Format, input, `d<p>text</p>`, output)
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Audit events %v, want %v", events, expected)
+	}
+	for i, e := range events {
+		if e != expected[i] {
+			t.Fatalf("Audit event %d %+v, want %+v", i, e, expected[i])
+		}
+		if !e.Script() || input[e.Offset:e.Offset+len(e.Snippet)] != e.Snippet {
+			t.Fatalf("Audit event %d offset %d does not match snippet %q", i, e.Offset, e.Snippet)
+		}
+	}
+}
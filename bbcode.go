@@ -0,0 +1,63 @@
+package sanitize
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Code blocks are converted first, so that tags inside them are left as text
+	bbCodeBlock = regexp.MustCompile(`(?is)\[code\](.*?)\[/code\]`)
+
+	// Simple formatting tags with an html equivalent
+	bbSimpleTag = regexp.MustCompile(`(?i)\[(/?)(b|i|u|s|quote)\]`)
+
+	// Links and images, the url is checked by the policy after conversion
+	bbURL      = regexp.MustCompile(`(?is)\[url\](.*?)\[/url\]`)
+	bbNamedURL = regexp.MustCompile(`(?is)\[url=([^\]]*)\](.*?)\[/url\]`)
+	bbImage    = regexp.MustCompile(`(?is)\[img\](.*?)\[/img\]`)
+)
+
+// Html elements used for simple bbcode tags
+var bbTags = map[string]string{"b": "b", "i": "i", "u": "u", "s": "s", "quote": "blockquote"}
+
+// BBCodePolicy returns a policy allowing the html produced by BBCode.
+func BBCodePolicy() *Policy {
+	return &Policy{
+		Tags:          []string{"b", "i", "u", "s", "blockquote", "a", "img", "pre", "code", "br"},
+		Attributes:    []string{"href", "src", "alt"},
+		URLAttributes: []string{"src"},
+		URLs:          URLOptions{RejectConfusable: true},
+	}
+}
+
+// BBCode converts forum bbcode to html, supporting [b], [i], [u], [s], [quote], [code], [url], [url=...] and [img].
+// Any html in s is escaped first, and the result is sanitized with BBCodePolicy, so links and images
+// must use absolute http or https urls. Line breaks are converted to <br>, except inside code blocks.
+func BBCode(s string) (string, error) {
+	s = template.HTMLEscapeString(NormalizeNewlines(s))
+
+	b := strings.Builder{}
+	start := 0
+	for _, m := range bbCodeBlock.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(bbCodeText(s[start:m[0]]))
+		b.WriteString("<pre><code>" + s[m[2]:m[3]] + "</code></pre>")
+		start = m[1]
+	}
+	b.WriteString(bbCodeText(s[start:]))
+
+	return BBCodePolicy().Sanitize(b.String())
+}
+
+// bbCodeText converts bbcode tags in escaped text outside code blocks to html.
+func bbCodeText(s string) string {
+	s = bbSimpleTag.ReplaceAllStringFunc(s, func(tag string) string {
+		m := bbSimpleTag.FindStringSubmatch(tag)
+		return "<" + m[1] + bbTags[strings.ToLower(m[2])] + ">"
+	})
+	s = bbNamedURL.ReplaceAllString(s, `<a href="$1">$2</a>`)
+	s = bbURL.ReplaceAllString(s, `<a href="$1">$1</a>`)
+	s = bbImage.ReplaceAllString(s, `<img src="$1">`)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
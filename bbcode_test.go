@@ -0,0 +1,31 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var bbCodeTests = []Test{
+	{"[b]bold[/b] and [I]italic[/I]", `<b>bold</b> and <i>italic</i>`},
+	{"[quote]wise words[/quote]", `<blockquote>wise words</blockquote>`},
+	{"[url]https://example.com[/url]", `<a href="https://example.com">https://example.com</a>`},
+	{"[url=https://example.com/a?b=1&c=2]site[/url]", `<a href="https://example.com/a?b=1&amp;c=2">site</a>`},
+	{"[url=javascript:alert(1)]click[/url]", `<a>click</a>`},
+	{`[url=https://x.com" onclick="alert(1)]x[/url]`, `<a>x</a>`},
+	{"[img]https://example.com/a.png[/img]", `<img src="https://example.com/a.png">`},
+	{"[img]/relative.png[/img]", `<img>`},
+	{"[code]<b>[b]not bold[/b]</b>\nline[/code]", "<pre><code>&lt;b&gt;[b]not bold[/b]&lt;/b&gt;\nline</code></pre>"},
+	{"<script>alert(1)</script>", `&lt;script&gt;alert(1)&lt;/script&gt;`},
+	{"line one\r\nline two", `line one<br>line two`},
+}
+
+func TestBBCode(t *testing.T) {
+	for _, test := range bbCodeTests {
+		output, err := BBCode(test.input)
+		if err != nil {
+			t.Fatalf(Format, test.input, test.expected, err)
+		}
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
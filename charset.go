@@ -0,0 +1,26 @@
+package sanitize
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// HTMLFromReader reads html in any charset supported by browsers and sanitizes it as HTMLAllowing does
+// with the default tags and attributes, returning utf-8. The charset is found from a byte order mark,
+// the charset parameter of contentType if not empty, or a meta tag in the html, in that order,
+// and is windows-1252 if none is found and the html is not valid utf-8.
+func HTMLFromReader(r io.Reader, contentType string) (string, error) {
+	reader, err := charset.NewReader(r, contentType)
+	if err != nil {
+		return "", err
+	}
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	// The byte order mark is kept by the utf-8 decoder
+	return Sanitize(strings.TrimPrefix(string(b), "\ufeff"))
+}
@@ -0,0 +1,32 @@
+package sanitize
+
+import (
+	"bytes"
+	"testing"
+)
+
+var htmlFromReaderTests = []struct {
+	input       []byte
+	contentType string
+	expected    string
+}{
+	// Привет in windows-1251, with the charset in the content type
+	{[]byte("<p>\xcf\xf0\xe8\xe2\xe5\xf2</p><script>x</script>"), "text/html; charset=windows-1251", "<p>Привет</p>"},
+	// こんにちは in shift_jis, with the charset in a meta tag
+	{[]byte("<meta charset=\"shift_jis\"><p>\x82\xb1\x82\xf1\x82\xc9\x82\xbf\x82\xcd</p>"), "", "<p>こんにちは</p>"},
+	// A byte order mark overrides the content type
+	{[]byte("\xef\xbb\xbf<b>caf\xc3\xa9</b>"), "text/html; charset=iso-8859-1", "<b>café</b>"},
+	// Utf-16 with a byte order mark
+	{[]byte("\xff\xfe<\x00i\x00>\x00h\x00i\x00<\x00/\x00i\x00>\x00"), "", "<i>hi</i>"},
+	// Legacy pages without a charset default to windows-1252
+	{[]byte("<p>\x93quoted\x94 caf\xe9</p>"), "text/html", "<p>“quoted” café</p>"},
+}
+
+func TestHTMLFromReader(t *testing.T) {
+	for _, test := range htmlFromReaderTests {
+		output, err := HTMLFromReader(bytes.NewReader(test.input), test.contentType)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CJKStrategy selects how chinese, japanese and korean characters are treated in slugs and names.
+type CJKStrategy int
+
+// Supported strategies for CJK characters.
+const (
+	// CJKRemove removes CJK characters, as for other characters which cannot be transliterated.
+	CJKRemove CJKStrategy = iota
+
+	// CJKTransliterate romanizes japanese kana (Hepburn) and korean hangul (Revised Romanization),
+	// and chinese characters using SlugOptions.CJKTransliterator, which may provide pinyin or romaji readings.
+	CJKTransliterate
+
+	// CJKPreserve keeps CJK characters, percent-encoded.
+	CJKPreserve
+)
+
+// isCJK reports whether r is a chinese, japanese or korean character.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// Hepburn romanizations of hiragana, katakana are converted to hiragana before lookup.
+var kana = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゎ': "wa", 'ゔ': "vu",
+}
+
+// Small kana which combine with the preceding kana, as in きゃ kya.
+var smallKana = map[rune]string{'ゃ': "a", 'ゅ': "u", 'ょ': "o"}
+
+// romanizeKana replaces japanese kana in s with Hepburn romanization.
+func romanizeKana(s string) string {
+	if !strings.ContainsFunc(s, func(r rune) bool { return unicode.In(r, unicode.Hiragana, unicode.Katakana) }) {
+		return s
+	}
+
+	runes := []rune(s)
+	b := strings.Builder{}
+	double := false
+	for i := 0; i < len(runes); i++ {
+		r := toHiragana(runes[i])
+
+		// A small tsu doubles the following consonant, the long vowel mark is not written
+		if r == 'っ' {
+			double = true
+			continue
+		}
+		if r == 'ー' {
+			continue
+		}
+
+		syllable, ok := kana[r]
+		if !ok {
+			b.WriteRune(runes[i])
+			double = false
+			continue
+		}
+
+		// Combine with a following small ya, yu or yo
+		if i+1 < len(runes) && strings.HasSuffix(syllable, "i") && len(syllable) > 1 {
+			if vowel, ok := smallKana[toHiragana(runes[i+1])]; ok {
+				syllable = strings.TrimSuffix(syllable, "i")
+				if !strings.HasSuffix(syllable, "sh") && !strings.HasSuffix(syllable, "ch") && syllable != "j" {
+					syllable += "y"
+				}
+				syllable += vowel
+				i++
+			}
+		}
+
+		if double {
+			if strings.HasPrefix(syllable, "ch") {
+				b.WriteString("t")
+			} else {
+				b.WriteString(syllable[:1])
+			}
+			double = false
+		}
+		b.WriteString(syllable)
+	}
+	return b.String()
+}
+
+// toHiragana returns the hiragana equivalent of a katakana character, or r if it is not katakana.
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - 0x60
+	}
+	return r
+}
+
+// Revised Romanization of the jamo which make up hangul syllables.
+var (
+	hangulInitials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulVowels   = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulFinals   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+// hangulRomanizer transliterates hangul syllables using Revised Romanization, without sound change rules.
+type hangulRomanizer struct{}
+
+// Transliterate returns the romanization of a hangul syllable.
+func (hangulRomanizer) Transliterate(r rune) (string, bool) {
+	if r < 0xAC00 || r > 0xD7A3 {
+		return "", false
+	}
+	i := int(r - 0xAC00)
+	return hangulInitials[i/588] + hangulVowels[(i%588)/28] + hangulFinals[i%28], true
+}
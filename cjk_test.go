@@ -0,0 +1,32 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var pinyin = TransliterationMap{'北': "bei ", '京': "jing ", '欢': "huan ", '迎': "ying "}
+
+var cjkTests = []struct {
+	input    string
+	opts     SlugOptions
+	expected string
+}{
+	{"北京 News", SlugOptions{}, `news`},
+	{"北京 News", SlugOptions{CJK: CJKPreserve}, `%E5%8C%97%E4%BA%AC-news`},
+	{"欢迎 to 北京", SlugOptions{CJK: CJKTransliterate, CJKTransliterator: pinyin}, `huan-ying-to-bei-jing`},
+	{"ありがとう", SlugOptions{CJK: CJKTransliterate}, `arigatou`},
+	{"東京のきっぷ", SlugOptions{CJK: CJKTransliterate}, `nokippu`},
+	{"トウキョウ・チョコレート", SlugOptions{CJK: CJKTransliterate}, `toukyouchokoreto`},
+	{"まっちゃ", SlugOptions{CJK: CJKTransliterate}, `matcha`},
+	{"안녕하세요 서울", SlugOptions{CJK: CJKTransliterate}, `annyeonghaseyo-seoul`},
+	{"한국", SlugOptions{CJK: CJKTransliterate, Replacement: '-'}, `hanguk`},
+}
+
+func TestCJK(t *testing.T) {
+	for _, test := range cjkTests {
+		output := Slug(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Command sanitize works with html stored after sanitizing with the sanitize package.
+//
+// The migrate subcommand sanitizes stored html with a new policy and prints the elements and attributes
+// the new policy removes, so that the effect of tightening a policy can be checked before updating stored html:
+//
+//	sanitize migrate -from default -to markdown [-w] [files...]
+//
+// Html is read from each file, or from standard input if no files are given. With -w each file is
+// rewritten with the html sanitized by the new policy if it changed.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kennygrant/sanitize"
+)
+
+// Policies which may be named with -from and -to.
+var policies = map[string]func() *sanitize.Policy{
+	"bbcode":   sanitize.BBCodePolicy,
+	"code":     sanitize.CodePolicy,
+	"default":  sanitize.DefaultPolicy,
+	"email":    sanitize.EmailPolicy,
+	"feed":     sanitize.FeedPolicy,
+	"markdown": sanitize.MarkdownPolicy,
+	"media":    sanitize.MediaPolicy,
+	"office":   sanitize.OfficePastePolicy,
+	"table":    sanitize.TablePolicy,
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "migrate" {
+		fmt.Fprintln(os.Stderr, "usage: sanitize migrate -from policy -to policy [-w] [files...]")
+		os.Exit(2)
+	}
+	if err := migrate(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "sanitize:", err)
+		os.Exit(1)
+	}
+}
+
+// migrate runs the migrate subcommand with args, printing the changes for each input to w.
+func migrate(args []string, stdin io.Reader, w io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	names := policyNames()
+	fromName := flags.String("from", "default", "the policy the html was stored with, one of "+names)
+	toName := flags.String("to", "", "the policy to migrate to, one of "+names)
+	write := flags.Bool("w", false, "rewrite files which change with the html sanitized by the new policy")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	from, ok := policies[*fromName]
+	if !ok {
+		return fmt.Errorf("unknown policy %q for -from, use one of %s", *fromName, names)
+	}
+	to, ok := policies[*toName]
+	if !ok {
+		return fmt.Errorf("unknown policy %q for -to, use one of %s", *toName, names)
+	}
+
+	if flags.NArg() == 0 {
+		if *write {
+			return fmt.Errorf("-w requires files")
+		}
+		old, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		_, err = migrateHTML("-", string(old), from(), to(), w)
+		return err
+	}
+
+	for _, path := range flags.Args() {
+		old, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		output, err := migrateHTML(path, string(old), from(), to(), w)
+		if err != nil {
+			return err
+		}
+		if *write && output != string(old) {
+			if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migrateHTML migrates html read from name, printing the ChangeSet and returning the migrated html.
+func migrateHTML(name, old string, from, to *sanitize.Policy, w io.Writer) (string, error) {
+	output, changes, err := sanitize.Migrate(old, from, to)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+	if !changes.Changed {
+		fmt.Fprintf(w, "%s: unchanged\n", name)
+		return output, nil
+	}
+	fmt.Fprintf(w, "%s: changed\n", name)
+	for _, r := range changes.Removed {
+		if r.Attribute == "" {
+			fmt.Fprintf(w, "\tremoved <%s>\n", r.Tag)
+		} else {
+			fmt.Fprintf(w, "\tremoved %s=%q from <%s>\n", r.Attribute, r.Value, r.Tag)
+		}
+	}
+	return output, nil
+}
+
+// policyNames returns the names of the policies which may be used, separated by commas.
+func policyNames() string {
+	var names []string
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
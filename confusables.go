@@ -0,0 +1,203 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// A limited set of confusable characters from UTS #39, mapping characters which look like latin letters
+// to their latin prototype. This covers the cyrillic and greek letters most often used to spoof names,
+// and the latin letters, digits and symbols which are confused with each other.
+var confusables = map[rune]rune{
+	// Latin and common
+	'0': 'O',
+	'1': 'l',
+	'I': 'l',
+	'|': 'l',
+	'ǀ': 'l',
+	'ı': 'i',
+	'ɩ': 'i',
+	'ɑ': 'a',
+	'ɡ': 'g',
+	'ɪ': 'i',
+	'ʏ': 'y',
+	'ℓ': 'l',
+	'∣': 'l',
+
+	// Cyrillic
+	'а': 'a',
+	'в': 'b',
+	'е': 'e',
+	'һ': 'h',
+	'і': 'i',
+	'ј': 'j',
+	'к': 'k',
+	'ӏ': 'l',
+	'м': 'm',
+	'о': 'o',
+	'р': 'p',
+	'ԛ': 'q',
+	'г': 'r',
+	'ѕ': 's',
+	'т': 't',
+	'у': 'y',
+	'х': 'x',
+	'ԝ': 'w',
+	'с': 'c',
+	'ԁ': 'd',
+	'ԍ': 'G',
+	'ѵ': 'v',
+	'ү': 'y',
+	'з': '3',
+	'ь': 'b',
+	'А': 'A',
+	'В': 'B',
+	'Е': 'E',
+	'Н': 'H',
+	'І': 'I',
+	'Ј': 'J',
+	'К': 'K',
+	'М': 'M',
+	'О': 'O',
+	'Р': 'P',
+	'С': 'C',
+	'Ѕ': 'S',
+	'Т': 'T',
+	'Х': 'X',
+	'У': 'Y',
+	'Ԝ': 'W',
+	'Ѵ': 'V',
+	'Ү': 'Y',
+	'Ӏ': 'l',
+
+	// Greek
+	'α': 'a',
+	'ι': 'i',
+	'κ': 'k',
+	'ν': 'v',
+	'ο': 'o',
+	'ρ': 'p',
+	'υ': 'u',
+	'χ': 'x',
+	'ϲ': 'c',
+	'ϳ': 'j',
+	'Α': 'A',
+	'Β': 'B',
+	'Ε': 'E',
+	'Ζ': 'Z',
+	'Η': 'H',
+	'Ι': 'I',
+	'Κ': 'K',
+	'Μ': 'M',
+	'Ν': 'N',
+	'Ο': 'O',
+	'Ρ': 'P',
+	'Τ': 'T',
+	'Υ': 'Y',
+	'Χ': 'X',
+	'Ϲ': 'C',
+}
+
+// Skeleton returns the UTS #39 skeleton of s, a string used only for comparison in which each character
+// is replaced by the prototype it may be confused with. Two strings with the same skeleton look alike,
+// so an application may reject a username like аdmin (with a cyrillic а) whose skeleton matches admin.
+// Compatibility characters such as full width letters are also folded, the skeleton is case sensitive.
+func Skeleton(s string) string {
+	s = norm.NFKD.String(s)
+	s = strings.Map(func(r rune) rune {
+		if prototype, ok := confusables[r]; ok {
+			return prototype
+		}
+		return r
+	}, s)
+	return norm.NFD.String(s)
+}
+
+// Scripts which may be mixed within a single label, as in the highly restrictive profile of UTS #39.
+var (
+	japaneseScripts = []string{"Latin", "Han", "Hiragana", "Katakana"}
+	chineseScripts  = []string{"Latin", "Han", "Bopomofo"}
+	koreanScripts   = []string{"Latin", "Han", "Hangul"}
+)
+
+// ConfusableHost reports whether a host name (in unicode or punycode) contains a label
+// which mixes scripts, or which is written entirely in letters confusable with latin letters,
+// for example аpple.com where the first letter is cyrillic.
+func ConfusableHost(host string) bool {
+	host, err := idna.ToUnicode(strings.ToLower(host))
+	if err != nil {
+		return true
+	}
+	for _, label := range strings.Split(host, ".") {
+		if confusableLabel(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// confusableLabel reports whether a single host label mixes scripts or is a whole script confusable with latin.
+func confusableLabel(label string) bool {
+	scripts := labelScripts(label)
+	if len(scripts) > 1 {
+		return !allowedScripts(scripts)
+	}
+	if len(scripts) == 0 || scripts[0] == "Latin" {
+		return false
+	}
+
+	// A label in a single non-latin script is confusable if every letter looks like a latin letter
+	for _, r := range label {
+		if unicode.IsLetter(r) {
+			if _, ok := confusables[r]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// labelScripts returns the scripts used by letters in s, ignoring common and inherited characters.
+func labelScripts(s string) []string {
+	var scripts []string
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		for name, table := range unicode.Scripts {
+			if name == "Common" || name == "Inherited" {
+				continue
+			}
+			if unicode.Is(table, r) {
+				if !includes(scripts, name) {
+					scripts = append(scripts, name)
+				}
+				break
+			}
+		}
+	}
+	return scripts
+}
+
+// allowedScripts reports whether a set of scripts is one of the allowed combinations.
+func allowedScripts(scripts []string) bool {
+	for _, allowed := range [][]string{japaneseScripts, chineseScripts, koreanScripts} {
+		if subset(scripts, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// subset reports whether every string in a is included in b.
+func subset(a, b []string) bool {
+	for _, s := range a {
+		if !includes(b, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var confusableHosts = []struct {
+	input    string
+	expected bool
+}{
+	{"apple.com", false},
+	{"аpple.com", true},
+	{"аррӏе.com", true},
+	{"xn--80ak6aa92e.com", true},
+	{"пример.рф", false},
+	{"münchen.de", false},
+	{"日本語カタカナ.jp", false},
+	{"pаypal.example", true},
+}
+
+func TestConfusableHost(t *testing.T) {
+	for _, test := range confusableHosts {
+		output := ConfusableHost(test.input)
+		if output != test.expected {
+			t.Fatalf("\ninput:    %q\nexpected: %v\noutput:   %v", test.input, test.expected, output)
+		}
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"admin", "аdmin", true},
+		{"paypal", "раураl", true},
+		{"Il1|", "llll", true},
+		{"g00gle", "gOOgle", true},
+		{"\uff41\uff44\uff4d\uff49\uff4e", "admin", true},
+		{"caf\u00e9", "cafe\u0301", true},
+		{"admin", "Admin", false},
+		{"admin", "admins", false},
+		{"пример", "primer", false},
+	}
+	for _, test := range tests {
+		same := Skeleton(test.a) == Skeleton(test.b)
+		if same != test.same {
+			t.Fatalf("\ninput:    %q %q\nexpected: %v\noutput:   %v", test.a, test.b, test.same, same)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package sanitize
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// CSSEscape escapes a string for use as a css identifier, for example a class name or id from user input
+// in a selector, following the CSS.escape algorithm in the CSSOM specification.
+// Punctuation is escaped with a backslash, control characters and leading digits as hex escapes,
+// and NUL is replaced with U+FFFD. The result may also be used inside a quoted css string.
+func CSSEscape(s string) string {
+	b := strings.Builder{}
+	first, _ := utf8.DecodeRuneInString(s)
+	for i, r := range s {
+		switch {
+		case r == 0:
+			b.WriteRune(utf8.RuneError)
+		case (r >= 0x01 && r <= 0x1F) || r == 0x7F:
+			fmt.Fprintf(&b, `\%x `, r)
+		case i == 0 && r >= '0' && r <= '9':
+			fmt.Fprintf(&b, `\%x `, r)
+		case i == 1 && first == '-' && r >= '0' && r <= '9':
+			fmt.Fprintf(&b, `\%x `, r)
+		case i == 0 && r == '-' && len(s) == 1:
+			b.WriteString(`\-`)
+		case r >= 0x80 || r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z'):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+var (
+	// Values allowed in style declarations, without urls, escapes, comments or at-rules
+	legalStyleValue = regexp.MustCompile(`(?i)\A[a-z0-9\s#%.,+!'"()-]*\z`)
+
+	// Functions in style values
+	styleFunction = regexp.MustCompile(`(?i)([a-z-]*)\s*\(`)
+)
+
+// Functions allowed in style values, other functions such as url and expression are removed.
+var styleFunctions = []string{"rgb", "rgba", "hsl", "hsla"}
+
+// cleanStyle returns the declarations in a style attribute for the css properties listed,
+// removing other properties and values which are not simple keywords, lengths, colours or font names.
+func cleanStyle(style string, properties []string) string {
+	var declarations []string
+	for _, declaration := range strings.Split(style, ";") {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) != 2 || !includes(properties, strings.ToLower(strings.TrimSpace(parts[0]))) {
+			continue
+		}
+		if legalStyleDeclaration(parts[1]) {
+			declarations = append(declarations, strings.TrimSpace(declaration))
+		}
+	}
+	return strings.Join(declarations, ";")
+}
+
+// legalStyleDeclaration reports whether value contains only allowed characters and functions, with balanced quotes.
+func legalStyleDeclaration(value string) bool {
+	if !legalStyleValue.MatchString(value) || strings.Count(value, `"`)%2 != 0 || strings.Count(value, "'")%2 != 0 {
+		return false
+	}
+	for _, m := range styleFunction.FindAllStringSubmatch(value, -1) {
+		if !includes(styleFunctions, strings.ToLower(m[1])) {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	// Css which loads resources or runs script, not allowed in style elements given a nonce by Policy.CSP
+	unsafeStylesheet = regexp.MustCompile(`(?i)@import|(url|src|image|image-set|expression)\s*\(|behavior|binding|javascript:|\\`)
+
+	// Css comments, removed before stylesheets are checked
+	cssComment = regexp.MustCompile(`/\*[\s\S]*?\*/`)
+)
+
+// safeStylesheet reports whether css cannot load resources from urls or run script.
+// Escapes are not allowed, so that these cannot be hidden.
+func safeStylesheet(css string) bool {
+	return !unsafeStylesheet.MatchString(css) && !unsafeStylesheet.MatchString(cssComment.ReplaceAllString(css, ""))
+}
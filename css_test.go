@@ -0,0 +1,28 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var cssEscapeTests = []Test{
+	{"simple-class_name", `simple-class_name`},
+	{"", ``},
+	{"a.b#c", `a\.b\#c`},
+	{"1st", `\31 st`},
+	{"-2x", `-\32 x`},
+	{"-", `\-`},
+	{"--custom", `--custom`},
+	{"} body { background: red", `\}\ body\ \{\ background\:\ red`},
+	{`quote"and'`, `quote\"and\'`},
+	{"tab\tnull\x00", `tab\9 null` + "\ufffd"},
+	{"café", `café`},
+}
+
+func TestCSSEscape(t *testing.T) {
+	for _, test := range cssEscapeTests {
+		output := CSSEscape(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package sanitize
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Characters which cause spreadsheet applications to treat a cell as a formula
+const formulaPrefixes = "=+-@\t\r"
+
+// CSVCell makes a string safe to export as a cell in a csv file opened by spreadsheet applications,
+// so that user data cannot execute formulas such as =HYPERLINK or =cmd|' /C calc'!A0.
+// Cells starting with =, +, -, @, tab or carriage return are prefixed with a single quote,
+// following the OWASP guidance on csv injection. Numbers such as -1.5 are left unchanged.
+// Quoting the cell itself is left to the csv writer.
+func CSVCell(s string) string {
+	if s == "" || !strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	return "'" + s
+}
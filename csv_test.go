@@ -0,0 +1,29 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var csvCellTests = []Test{
+	{"plain text", `plain text`},
+	{"", ``},
+	{"=1+1", `'=1+1`},
+	{"=HYPERLINK(\"http://evil.com\",\"click\")", `'=HYPERLINK("http://evil.com","click")`},
+	{"+cmd|' /C calc'!A0", `'+cmd|' /C calc'!A0`},
+	{"-2+3", `'-2+3`},
+	{"@SUM(A1:A2)", `'@SUM(A1:A2)`},
+	{"\t=1", "'\t=1"},
+	{"\r=1", "'\r=1"},
+	{"-1.5", `-1.5`},
+	{"+44", `+44`},
+	{"a=b", `a=b`},
+}
+
+func TestCSVCell(t *testing.T) {
+	for _, test := range csvCellTests {
+		output := CSVCell(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package sanitize
+
+// CyrillicScheme selects the system used to transliterate cyrillic letters.
+type CyrillicScheme int
+
+// Supported cyrillic transliteration schemes.
+const (
+	// CyrillicPassport follows the ICAO system used in russian passports since 2013, for example щ becomes shch and я becomes ia.
+	CyrillicPassport CyrillicScheme = iota
+
+	// CyrillicBGN follows the BGN/PCGN system common in english texts, for example й becomes y and я becomes ya.
+	CyrillicBGN
+
+	// CyrillicScientific follows the scientific system, with diacritics flattened, for example ч becomes c and я becomes ja.
+	CyrillicScientific
+)
+
+// Letters used outside russian, which are transliterated the same way in every scheme.
+var cyrillicCommon = map[rune]string{
+	'є': "ie",
+	'ґ': "g",
+	'і': "i",
+	'ї': "i",
+	'ў': "u",
+	'ђ': "dj",
+	'ј': "j",
+	'љ': "lj",
+	'њ': "nj",
+	'ћ': "c",
+	'џ': "dz",
+}
+
+var cyrillicPassport = withCapitals(withCommon(map[rune]string{
+	'а': "a",
+	'б': "b",
+	'в': "v",
+	'г': "g",
+	'д': "d",
+	'е': "e",
+	'ё': "e",
+	'ж': "zh",
+	'з': "z",
+	'и': "i",
+	'й': "i",
+	'к': "k",
+	'л': "l",
+	'м': "m",
+	'н': "n",
+	'о': "o",
+	'п': "p",
+	'р': "r",
+	'с': "s",
+	'т': "t",
+	'у': "u",
+	'ф': "f",
+	'х': "kh",
+	'ц': "ts",
+	'ч': "ch",
+	'ш': "sh",
+	'щ': "shch",
+	'ъ': "ie",
+	'ы': "y",
+	'ь': "",
+	'э': "e",
+	'ю': "iu",
+	'я': "ia",
+}))
+
+var cyrillicBGN = withCapitals(withCommon(map[rune]string{
+	'а': "a",
+	'б': "b",
+	'в': "v",
+	'г': "g",
+	'д': "d",
+	'е': "e",
+	'ё': "yo",
+	'ж': "zh",
+	'з': "z",
+	'и': "i",
+	'й': "y",
+	'к': "k",
+	'л': "l",
+	'м': "m",
+	'н': "n",
+	'о': "o",
+	'п': "p",
+	'р': "r",
+	'с': "s",
+	'т': "t",
+	'у': "u",
+	'ф': "f",
+	'х': "kh",
+	'ц': "ts",
+	'ч': "ch",
+	'ш': "sh",
+	'щ': "shch",
+	'ъ': "",
+	'ы': "y",
+	'ь': "",
+	'э': "e",
+	'ю': "yu",
+	'я': "ya",
+}))
+
+var cyrillicScientific = withCapitals(withCommon(map[rune]string{
+	'а': "a",
+	'б': "b",
+	'в': "v",
+	'г': "g",
+	'д': "d",
+	'е': "e",
+	'ё': "ë",
+	'ж': "ž",
+	'з': "z",
+	'и': "i",
+	'й': "j",
+	'к': "k",
+	'л': "l",
+	'м': "m",
+	'н': "n",
+	'о': "o",
+	'п': "p",
+	'р': "r",
+	'с': "s",
+	'т': "t",
+	'у': "u",
+	'ф': "f",
+	'х': "x",
+	'ц': "c",
+	'ч': "č",
+	'ш': "š",
+	'щ': "šč",
+	'ъ': "",
+	'ы': "y",
+	'ь': "",
+	'э': "è",
+	'ю': "ju",
+	'я': "ja",
+}))
+
+// Ukrainian and bulgarian follow their own national systems for letters shared with russian.
+var (
+	ukrainianLetters = withCapitals(map[rune]string{
+		'г': "h",
+		'и': "y",
+		'й': "i",
+		'х': "kh",
+		'ц': "ts",
+		'щ': "shch",
+		'ь': "",
+		'ю': "iu",
+		'я': "ia",
+	})
+
+	bulgarianLetters = withCapitals(map[rune]string{
+		'ж': "zh",
+		'й': "y",
+		'х': "h",
+		'ц': "ts",
+		'ч': "ch",
+		'ш': "sh",
+		'щ': "sht",
+		'ъ': "a",
+		'ь': "y",
+		'ю': "yu",
+		'я': "ya",
+	})
+)
+
+// cyrillicTable returns the transliterations for a cyrillic scheme.
+func cyrillicTable(scheme CyrillicScheme) TransliterationMap {
+	switch scheme {
+	case CyrillicBGN:
+		return cyrillicBGN
+	case CyrillicScientific:
+		return cyrillicScientific
+	}
+	return cyrillicPassport
+}
+
+// withCommon adds the letters shared by all cyrillic schemes to m.
+func withCommon(m map[rune]string) map[rune]string {
+	for r, s := range cyrillicCommon {
+		m[r] = s
+	}
+	return m
+}
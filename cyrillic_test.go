@@ -0,0 +1,35 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var cyrillicTests = []struct {
+	input    string
+	opts     SlugOptions
+	expected string
+}{
+	{"Щука и Ёжик", SlugOptions{}, `shchuka-i-ezhik`},
+	{"Щука и Ёжик", SlugOptions{Cyrillic: CyrillicBGN}, `shchuka-i-yozhik`},
+	{"Щука и Ёжик", SlugOptions{Cyrillic: CyrillicScientific}, `scuka-i-ezik`},
+	{"Юрий Гагарин", SlugOptions{}, `iurii-gagarin`},
+	{"Юрий Гагарин", SlugOptions{Cyrillic: CyrillicBGN}, `yuriy-gagarin`},
+	{"Київ, Україна", SlugOptions{Lang: "uk"}, `kyiv-ukraina`},
+	{"Гриць", SlugOptions{Lang: "uk"}, `hryts`},
+	{"България, Щастие", SlugOptions{Lang: "bg"}, `balgariya-shtastie`},
+	{"Београд, Љубљана", SlugOptions{}, `beograd-ljubljana`},
+}
+
+func TestCyrillic(t *testing.T) {
+	for _, test := range cyrillicTests {
+		output := Slug(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	output := Accents("Пётр Чайковский")
+	if output != "Petr Chaikovskii" {
+		t.Fatalf(Format, "Пётр Чайковский", "Petr Chaikovskii", output)
+	}
+}
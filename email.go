@@ -0,0 +1,142 @@
+package sanitize
+
+import (
+	"errors"
+	"mime"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Errors returned by EmailAddress when an address cannot be made safe.
+var (
+	ErrEmailInvalid = errors.New("sanitize: invalid email address")
+	ErrEmailHost    = errors.New("sanitize: invalid email domain")
+)
+
+// The local part of an address must be a dot-atom, quoted local parts are not allowed
+var legalEmailLocal = regexp.MustCompile(`\A[\p{L}\p{N}!#$%&'*+/=?^_{|}~-]+(\.[\p{L}\p{N}!#$%&'*+/=?^_{|}~-]+)*\z`)
+
+// EmailHeaderValue makes a string safe to use as an email header value such as a subject,
+// removing line breaks and control characters as Header does, and encoding non-ascii text
+// as an RFC 2047 encoded word.
+func EmailHeaderValue(s string) string {
+	return mime.QEncoding.Encode("utf-8", Header(s))
+}
+
+// EmailAddress validates and normalizes an email address for use in mail headers.
+// Any display name is removed, so that only the bare address is returned,
+// and addresses containing line breaks, control characters, quoted local parts,
+// several addresses or ip address literals are rejected.
+// The domain is lowercased and converted to punycode.
+func EmailAddress(s string) (string, error) {
+	if strings.ContainsFunc(s, unicode.IsControl) {
+		return "", ErrEmailInvalid
+	}
+
+	addr, err := mail.ParseAddress(strings.TrimSpace(s))
+	if err != nil {
+		return "", ErrEmailInvalid
+	}
+
+	i := strings.LastIndex(addr.Address, "@")
+	if i < 0 {
+		return "", ErrEmailInvalid
+	}
+	local, domain := addr.Address[:i], addr.Address[i+1:]
+	if !legalEmailLocal.MatchString(local) {
+		return "", ErrEmailInvalid
+	}
+
+	// Domains must be names, not ip literals or single labels like localhost
+	if strings.HasPrefix(domain, "[") || !strings.Contains(domain, ".") {
+		return "", ErrEmailHost
+	}
+	domain, err = cleanHost(domain)
+	if err != nil {
+		return "", ErrEmailHost
+	}
+
+	return local + "@" + domain, nil
+}
+
+// EmailPolicy returns a policy for html email such as newsletters, allowing the table layouts, inline styles and
+// presentational attributes such as bgcolor which email clients require. Styles are limited to properties for
+// text, colours, borders and spacing, without urls or positioning, see Policy.StyleProperties. Links and images must use absolute
+// http or https urls. Set ConditionalComments to keep the conditional comments and VML used by Outlook.
+func EmailPolicy() *Policy {
+	return &Policy{
+		Tags: []string{
+			"a", "b", "blockquote", "br", "center", "div", "em", "font", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img",
+			"li", "ol", "p", "s", "small", "span", "strong", "sub", "sup", "table", "tbody", "td", "tfoot", "th", "thead", "tr", "u", "ul",
+		},
+		Attributes: []string{
+			"href", "src", "alt", "title", "width", "height", "style", "class", "align", "valign", "bgcolor", "background",
+			"border", "cellpadding", "cellspacing", "color", "face", "size", "colspan", "rowspan", "lang", "dir",
+		},
+		StyleProperties: emailStyleProperties,
+		URLAttributes:   []string{"src"},
+		URLs:            URLOptions{Schemes: []string{"http", "https", "mailto"}, RejectConfusable: true},
+	}
+}
+
+// Css properties allowed in style attributes by EmailPolicy, including v-text-anchor used by VML.
+var emailStyleProperties = []string{
+	"background-color", "border", "border-bottom", "border-collapse", "border-color", "border-left", "border-radius",
+	"border-right", "border-spacing", "border-style", "border-top", "border-width", "color", "direction", "display",
+	"font", "font-family", "font-size", "font-style", "font-weight", "height", "letter-spacing", "line-height",
+	"margin", "margin-bottom", "margin-left", "margin-right", "margin-top", "max-width", "min-width",
+	"mso-line-height-rule", "padding", "padding-bottom", "padding-left", "padding-right", "padding-top",
+	"text-align", "text-decoration", "text-transform", "v-text-anchor", "vertical-align", "white-space", "width",
+	"word-break",
+}
+
+var (
+	// Conditional comments with content read only by Outlook, such as <!--[if mso]>...<![endif]-->
+	conditionalComment = regexp.MustCompile(`(?is)\A(\[if [a-z0-9 !&|()]+\]>)(.*)(<!\[endif\])\z`)
+
+	// The start and end of downlevel-revealed comments, with content read by other clients, such as <!--[if !mso]><!-->
+	downlevelRevealed = regexp.MustCompile(`(?i)\A(\[if [a-z0-9 !&|()]+\]><!|<!\[endif\])\z`)
+)
+
+// VML elements used by Outlook for fallbacks such as rounded buttons and background images,
+// allowed inside conditional comments. They take attributes allowed by the policy such as style, and vmlAttributes.
+var vmlTags = []string{"v:rect", "v:roundrect", "v:oval", "v:line", "v:fill", "v:stroke", "v:shadow", "v:textbox", "v:image", "w:anchorlock", "o:p"}
+
+// Attributes of VML elements allowed inside conditional comments.
+var vmlAttributes = []string{
+	"xmlns:v", "xmlns:o", "xmlns:w", "arcsize", "strokecolor", "strokeweight", "stroke", "fillcolor", "fill",
+	"type", "color", "color2", "inset", "fitshape", "coordsize", "origin", "position", "aspect", "opacity",
+}
+
+// Namespaces of VML elements.
+var vmlNamespaces = []string{"urn:schemas-microsoft-com:vml", "urn:schemas-microsoft-com:office:office", "urn:schemas-microsoft-com:office:word"}
+
+// conditionalComment returns the html for a conditional comment kept by Policy.ConditionalComments,
+// with its content sanitized by the policy, or false if the comment should be removed.
+func (p *Policy) conditionalComment(data string, report *Report) (string, bool) {
+	if downlevelRevealed.MatchString(data) {
+		return "<!--" + data + "-->", true
+	}
+
+	m := conditionalComment.FindStringSubmatch(data)
+	if m == nil {
+		return "", false
+	}
+
+	// Removals from the content are audited by the outer policy, with the comment as the snippet
+	c := *p
+	c.Tags = append(append([]string{}, p.Tags...), vmlTags...)
+	c.Attributes = append(append([]string{}, p.Attributes...), vmlAttributes...)
+	c.ConditionalComments = false
+	c.Audit = nil
+	c.PartialOutput = false
+	content, err := c.sanitizeTokens(m[2], report)
+
+	// The content must not end the comment early
+	if err != nil || strings.Contains(content, "-->") || strings.Contains(content, "--!>") {
+		return "", false
+	}
+	return "<!--" + m[1] + content + m[3] + "-->", true
+}
@@ -0,0 +1,88 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var emailHeaderTests = []Test{
+	{"Your order", `Your order`},
+	{"Hello\r\nBcc: victim@example.com", `Hello Bcc: victim@example.com`},
+	{"Grüße", `=?utf-8?q?Gr=C3=BC=C3=9Fe?=`},
+}
+
+func TestEmailHeaderValue(t *testing.T) {
+	for _, test := range emailHeaderTests {
+		output := EmailHeaderValue(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var emailAddressTests = []struct {
+	input    string
+	expected string
+	err      error
+}{
+	{"alice@example.com", `alice@example.com`, nil},
+	{"  Alice@Example.COM ", `Alice@example.com`, nil},
+	{"Alice <alice@example.com>", `alice@example.com`, nil},
+	{`"Bob <admin@bank.com>" <bob@evil.com>`, `bob@evil.com`, nil},
+	{"first.last+tag@sub.example.org", `first.last+tag@sub.example.org`, nil},
+	{"user@münchen.de", `user@xn--mnchen-3ya.de`, nil},
+	{"alice@example.com\r\nBcc: victim@example.com", ``, ErrEmailInvalid},
+	{"alice@example.com, bob@example.com", ``, ErrEmailInvalid},
+	{`"quoted local"@example.com`, ``, ErrEmailInvalid},
+	{"not an address", ``, ErrEmailInvalid},
+	{"root@localhost", ``, ErrEmailHost},
+	{"user@[127.0.0.1]", ``, ErrEmailHost},
+	{"user@exa_mple.com", ``, ErrEmailHost},
+}
+
+func TestEmailAddress(t *testing.T) {
+	for _, test := range emailAddressTests {
+		output, err := EmailAddress(test.input)
+		if output != test.expected || err != test.err {
+			t.Fatalf("\ninput:    %q\nexpected: %q %v\noutput:   %q %v", test.input, test.expected, test.err, output, err)
+		}
+	}
+}
+
+var emailPolicyTests = []Test{
+	{`<table width="600" bgcolor="#ffffff" onclick="x()"><tr><td style="padding:10px" valign="top"><a href="https://example.com">Shop</a></td></tr></table>`, `<table width="600" bgcolor="#ffffff"><tr><td style="padding:10px" valign="top"><a href="https://example.com">Shop</a></td></tr></table>`},
+	{`<img src="/logo.png" alt="Logo"><a href="javascript:alert(1)">x</a>`, `<img alt="Logo"><a>x</a>`},
+	{`<!--[if mso]><p>Outlook</p><![endif]--><p>Other</p>`, `<p>Other</p>`},
+	{`<p style="color: #333; font-family: 'Helvetica Neue', Arial; background-color: rgb(1, 2, 3) !important">x</p>`, `<p style="color: #333;font-family: &#39;Helvetica Neue&#39;, Arial;background-color: rgb(1, 2, 3) !important">x</p>`},
+	{`<p style="width: expression(alert(1)); behavior: url(x.htc); -moz-binding: url(x.xml#x); padding: 4px">x</p>`, `<p style="padding: 4px">x</p>`},
+	{`<div style="background-color: url(https://tracker.example.com/open.gif); position: fixed; top: 0">x</div>`, `<div>x</div>`},
+	{`<p style="font-family: 'a;b'; color: red\3b">x</p>`, `<p>x</p>`},
+}
+
+var conditionalCommentTests = []Test{
+	{`<!--[if mso]><table><tr><td>Outlook</td></tr></table><![endif]-->`, `<!--[if mso]><table><tr><td>Outlook</td></tr></table><![endif]-->`},
+	{`<!--[if gte mso 9]><v:roundrect xmlns:v="urn:schemas-microsoft-com:vml" href="https://example.com" style="height:40px;width:200px" arcsize="10%" fillcolor="#556270" onclick="x()"><w:anchorlock/><center>Buy</center></v:roundrect><![endif]-->`,
+		`<!--[if gte mso 9]><v:roundrect xmlns:v="urn:schemas-microsoft-com:vml" href="https://example.com" style="height:40px;width:200px" arcsize="10%" fillcolor="#556270"><w:anchorlock/><center>Buy</center></v:roundrect><![endif]-->`},
+	{`<!--[if mso]><script>alert(1)</script><v:fill src="javascript:alert(1)" xmlns:v="http://evil.example.com" type="tile"/><![endif]-->`, `<!--[if mso]><v:fill type="tile"/><![endif]-->`},
+	{`<!--[if !mso]><!--><a href="https://example.com">Button</a><!--<![endif]-->`, `<!--[if !mso]><!--><a href="https://example.com">Button</a><!--<![endif]-->`},
+	{`<!--[if mso]><p title="--&gt;<img src=x>">x</p><![endif]-->`, `<!--[if mso]><p title="--&gt;&lt;img src=x&gt;">x</p><![endif]-->`},
+	{`<!--[if mso]><style>p{}--></style><![endif]--><!--[if <x>]>x<![endif]--><!-- comment --><p>Text</p>`, `<p>Text</p>`},
+	{`<script><!--[if mso]>x<![endif]--></script>`, ``},
+}
+
+func TestEmailPolicy(t *testing.T) {
+	p := EmailPolicy()
+	for _, test := range emailPolicyTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	p.ConditionalComments = true
+	for _, test := range conditionalCommentTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// EmojiPolicy selects how emoji are treated by Emoji and in slugs.
+type EmojiPolicy int
+
+// Supported emoji policies.
+const (
+	// EmojiDefault leaves the usual behaviour unchanged, emoji are kept in text and removed from slugs.
+	EmojiDefault EmojiPolicy = iota
+
+	// EmojiStrip removes emoji, including any modifiers and joiners in emoji sequences.
+	EmojiStrip
+
+	// EmojiKeep keeps emoji, percent-encoded in slugs.
+	EmojiKeep
+
+	// EmojiAlias replaces emoji with :shortcode: aliases in text, or the shortcode words in slugs.
+	// Emoji without a known alias are removed.
+	EmojiAlias
+)
+
+// Shortcode aliases for common emoji, following the names used by github and slack.
+var emojiAliases = map[string]string{
+	"😀":    "grinning",
+	"😃":    "smiley",
+	"😄":    "smile",
+	"😁":    "grin",
+	"😆":    "laughing",
+	"😅":    "sweat_smile",
+	"😂":    "joy",
+	"🤣":    "rofl",
+	"🙂":    "slightly_smiling_face",
+	"🙃":    "upside_down_face",
+	"😉":    "wink",
+	"😊":    "blush",
+	"😇":    "innocent",
+	"😍":    "heart_eyes",
+	"🤩":    "star_struck",
+	"😘":    "kissing_heart",
+	"😋":    "yum",
+	"😜":    "stuck_out_tongue_winking_eye",
+	"🤔":    "thinking",
+	"🤐":    "zipper_mouth_face",
+	"😐":    "neutral_face",
+	"😑":    "expressionless",
+	"😶":    "no_mouth",
+	"😏":    "smirk",
+	"😒":    "unamused",
+	"🙄":    "roll_eyes",
+	"😬":    "grimacing",
+	"😌":    "relieved",
+	"😔":    "pensive",
+	"😴":    "sleeping",
+	"😷":    "mask",
+	"🤒":    "face_with_thermometer",
+	"🤯":    "exploding_head",
+	"🥳":    "partying_face",
+	"😎":    "sunglasses",
+	"🤓":    "nerd_face",
+	"😕":    "confused",
+	"😟":    "worried",
+	"😮":    "open_mouth",
+	"😲":    "astonished",
+	"😳":    "flushed",
+	"🥺":    "pleading_face",
+	"😢":    "cry",
+	"😭":    "sob",
+	"😱":    "scream",
+	"😞":    "disappointed",
+	"😩":    "weary",
+	"😤":    "triumph",
+	"😡":    "rage",
+	"😠":    "angry",
+	"🤬":    "cursing_face",
+	"😈":    "smiling_imp",
+	"💀":    "skull",
+	"💩":    "poop",
+	"🤡":    "clown_face",
+	"👻":    "ghost",
+	"👽":    "alien",
+	"🤖":    "robot",
+	"🙈":    "see_no_evil",
+	"💋":    "kiss",
+	"💯":    "100",
+	"💥":    "boom",
+	"💫":    "dizzy",
+	"💬":    "speech_balloon",
+	"💤":    "zzz",
+	"👋":    "wave",
+	"👌":    "ok_hand",
+	"✌":    "v",
+	"🤞":    "crossed_fingers",
+	"👈":    "point_left",
+	"👉":    "point_right",
+	"👆":    "point_up_2",
+	"👇":    "point_down",
+	"👍":    "+1",
+	"👎":    "-1",
+	"✊":    "fist",
+	"👊":    "punch",
+	"👏":    "clap",
+	"🙌":    "raised_hands",
+	"🙏":    "pray",
+	"💪":    "muscle",
+	"👀":    "eyes",
+	"🧠":    "brain",
+	"👶":    "baby",
+	"❤":    "heart",
+	"🧡":    "orange_heart",
+	"💛":    "yellow_heart",
+	"💚":    "green_heart",
+	"💙":    "blue_heart",
+	"💜":    "purple_heart",
+	"🖤":    "black_heart",
+	"💔":    "broken_heart",
+	"💕":    "two_hearts",
+	"💖":    "sparkling_heart",
+	"🐶":    "dog",
+	"🐱":    "cat",
+	"🦊":    "fox_face",
+	"🐻":    "bear",
+	"🐼":    "panda_face",
+	"🐸":    "frog",
+	"🐵":    "monkey_face",
+	"🐔":    "chicken",
+	"🐧":    "penguin",
+	"🦄":    "unicorn",
+	"🐝":    "bee",
+	"🐛":    "bug",
+	"🦋":    "butterfly",
+	"🐢":    "turtle",
+	"🐍":    "snake",
+	"🐙":    "octopus",
+	"🐳":    "whale",
+	"🌸":    "cherry_blossom",
+	"🌹":    "rose",
+	"🌻":    "sunflower",
+	"🌲":    "evergreen_tree",
+	"🌵":    "cactus",
+	"🍀":    "four_leaf_clover",
+	"🍁":    "maple_leaf",
+	"🍎":    "apple",
+	"🍋":    "lemon",
+	"🍌":    "banana",
+	"🍉":    "watermelon",
+	"🍓":    "strawberry",
+	"🍕":    "pizza",
+	"🍔":    "hamburger",
+	"🍟":    "fries",
+	"🌮":    "taco",
+	"🍣":    "sushi",
+	"🍰":    "cake",
+	"🎂":    "birthday",
+	"🍪":    "cookie",
+	"🍫":    "chocolate_bar",
+	"☕":    "coffee",
+	"🍺":    "beer",
+	"🍻":    "beers",
+	"🍷":    "wine_glass",
+	"🌍":    "earth_africa",
+	"🌎":    "earth_americas",
+	"🌏":    "earth_asia",
+	"🏠":    "house",
+	"🚀":    "rocket",
+	"🚗":    "car",
+	"✈":    "airplane",
+	"⌚":    "watch",
+	"⏰":    "alarm_clock",
+	"⌛":    "hourglass",
+	"🌙":    "crescent_moon",
+	"☀":    "sunny",
+	"⭐":    "star",
+	"🌟":    "star2",
+	"☁":    "cloud",
+	"⚡":    "zap",
+	"❄":    "snowflake",
+	"🔥":    "fire",
+	"💧":    "droplet",
+	"🌈":    "rainbow",
+	"🎉":    "tada",
+	"🎈":    "balloon",
+	"🎁":    "gift",
+	"🏆":    "trophy",
+	"⚽":    "soccer",
+	"🏀":    "basketball",
+	"🎮":    "video_game",
+	"🎵":    "musical_note",
+	"🎶":    "notes",
+	"🎸":    "guitar",
+	"📱":    "iphone",
+	"💻":    "computer",
+	"📷":    "camera",
+	"💡":    "bulb",
+	"📚":    "books",
+	"📝":    "memo",
+	"📌":    "pushpin",
+	"📎":    "paperclip",
+	"🔒":    "lock",
+	"🔑":    "key",
+	"🔔":    "bell",
+	"💰":    "moneybag",
+	"💎":    "gem",
+	"🔧":    "wrench",
+	"⚙":    "gear",
+	"🔗":    "link",
+	"✅":    "white_check_mark",
+	"✔":    "heavy_check_mark",
+	"❌":    "x",
+	"❓":    "question",
+	"❗":    "exclamation",
+	"⚠":    "warning",
+	"🚫":    "no_entry_sign",
+	"♻":    "recycle",
+	"✨":    "sparkles",
+	"👨‍💻":  "man_technologist",
+	"👩‍💻":  "woman_technologist",
+	"🏳️‍🌈": "rainbow_flag",
+}
+
+// isEmoji reports whether r is an emoji which may start an emoji sequence.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, transport, flags and supplemental symbols
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // Miscellaneous technical, such as ⌚ and ⏰
+		return true
+	case r == 0x2B50 || r == 0x2B55 || r == 0x2B1B || r == 0x2B1C || r == 0x2B05 || r == 0x2B06 || r == 0x2B07:
+		return true
+	case r == 0x203C || r == 0x2049 || r == 0x3030 || r == 0x303D:
+		return true
+	}
+	return false
+}
+
+// isEmojiComponent reports whether r modifies or joins emoji, such as a variation selector,
+// skin tone modifier, zero width joiner, keycap or tag character.
+func isEmojiComponent(r rune) bool {
+	switch {
+	case r == 0x200D || r == 0xFE0E || r == 0xFE0F || r == 0x20E3:
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return true
+	case r >= 0xE0020 && r <= 0xE007F:
+		return true
+	}
+	return false
+}
+
+// replaceEmoji calls f with each emoji sequence in s, replacing the sequence with the result.
+// A sequence is an emoji followed by any modifiers, or several emoji joined by zero width joiners,
+// and pairs of regional indicators are treated as a single flag.
+func replaceEmoji(s string, f func(string) string) string {
+	if !strings.ContainsFunc(s, func(r rune) bool { return isEmoji(r) || r == 0x20E3 }) {
+		return s
+	}
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !isEmoji(r) && !isKeycap(s[i:]) {
+			b.WriteRune(r)
+			i += size
+			continue
+		}
+
+		// Consume the whole sequence
+		end := i + size
+		regional := isRegionalIndicator(r)
+		for end < len(s) {
+			next, nextSize := utf8.DecodeRuneInString(s[end:])
+			joined := strings.HasSuffix(s[i:end], "\u200d")
+			if isEmojiComponent(next) || (joined && isEmoji(next)) {
+				end += nextSize
+				continue
+			}
+			if regional && isRegionalIndicator(next) {
+				end += nextSize
+				regional = false
+				continue
+			}
+			break
+		}
+
+		b.WriteString(f(s[i:end]))
+		i = end
+	}
+	return b.String()
+}
+
+// isKeycap reports whether s starts with a keycap sequence such as 1️⃣.
+func isKeycap(s string) bool {
+	if s == "" || !strings.ContainsRune("0123456789#*", rune(s[0])) {
+		return false
+	}
+	s = strings.TrimPrefix(s[1:], "\ufe0f")
+	return strings.HasPrefix(s, "\u20e3")
+}
+
+// isRegionalIndicator reports whether r is one of the letters used in pairs for flags.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// emojiAlias returns the shortcode for an emoji sequence, ignoring variation selectors and skin tones,
+// or an empty string if it has none.
+func emojiAlias(seq string) string {
+	if alias, ok := emojiAliases[seq]; ok {
+		return alias
+	}
+	base := strings.Map(func(r rune) rune {
+		if r == 0xFE0E || r == 0xFE0F || (r >= 0x1F3FB && r <= 0x1F3FF) {
+			return -1
+		}
+		return r
+	}, seq)
+	return emojiAliases[base]
+}
+
+// Emoji applies an emoji policy to text, removing emoji or replacing them with :shortcode: aliases.
+// EmojiDefault and EmojiKeep return s unchanged.
+func Emoji(s string, policy EmojiPolicy) string {
+	switch policy {
+	case EmojiStrip:
+		return replaceEmoji(s, func(string) string { return "" })
+	case EmojiAlias:
+		return replaceEmoji(s, func(seq string) string {
+			if alias := emojiAlias(seq); alias != "" {
+				return ":" + alias + ":"
+			}
+			return ""
+		})
+	}
+	return s
+}
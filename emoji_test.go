@@ -0,0 +1,54 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var emojiTests = []struct {
+	input    string
+	policy   EmojiPolicy
+	expected string
+}{
+	{"Ship it 🚀🔥", EmojiDefault, `Ship it 🚀🔥`},
+	{"Ship it 🚀🔥", EmojiKeep, `Ship it 🚀🔥`},
+	{"Ship it 🚀🔥", EmojiStrip, `Ship it `},
+	{"Ship it 🚀🔥", EmojiAlias, `Ship it :rocket::fire:`},
+	{"Nice 👍🏽 work", EmojiAlias, `Nice :+1: work`},
+	{"Nice 👍🏽 work", EmojiStrip, `Nice  work`},
+	{"I ❤️ Go", EmojiAlias, `I :heart: Go`},
+	{"Dev 👩‍💻 team", EmojiAlias, `Dev :woman_technologist: team`},
+	{"Dev 👩‍💻 team", EmojiStrip, `Dev  team`},
+	{"Flag 🇬🇧🇫🇷 pair", EmojiStrip, `Flag  pair`},
+	{"Press 1️⃣ now", EmojiStrip, `Press  now`},
+	{"Unknown 🦩 bird", EmojiAlias, `Unknown  bird`},
+	{"नमस्‍ते", EmojiStrip, "नमस्‍ते"},
+}
+
+func TestEmoji(t *testing.T) {
+	for _, test := range emojiTests {
+		output := Emoji(test.input, test.policy)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var emojiSlugTests = []struct {
+	input    string
+	policy   EmojiPolicy
+	expected string
+}{
+	{"I ❤️ Go 🚀", EmojiDefault, `i-go`},
+	{"I ❤️ Go 🚀", EmojiStrip, `i-go`},
+	{"I ❤️ Go 🚀", EmojiAlias, `i-heart-go-rocket`},
+	{"I ❤️ Go 🚀", EmojiKeep, `i-%E2%9D%A4%EF%B8%8F-go-%F0%9F%9A%80`},
+}
+
+func TestEmojiSlug(t *testing.T) {
+	for _, test := range emojiSlugTests {
+		output := Slug(test.input, SlugOptions{Emoji: test.policy})
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package sanitize
+
+import (
+	"html/template"
+	"net/url"
+)
+
+// Context identifies where untrusted text is to be inserted, so that Escape can apply the right escaping.
+type Context int
+
+// Supported contexts for Escape.
+const (
+	// ContextHTMLText is text content in an html element.
+	ContextHTMLText Context = iota
+
+	// ContextHTMLAttr is a quoted html attribute value.
+	ContextHTMLAttr
+
+	// ContextURLQuery is a key or value in a url query string.
+	ContextURLQuery
+
+	// ContextJSString is a quoted javascript string literal, including one in an html script element.
+	ContextJSString
+
+	// ContextCSSValue is a css identifier or quoted css string, for example a class name in a selector.
+	ContextCSSValue
+
+	// ContextXMLText is xml text content or a quoted attribute value.
+	ContextXMLText
+)
+
+// Escape escapes untrusted text for insertion into the context given, in the same way as html/template
+// but for use outside templates. An unknown context returns an empty string.
+func Escape(s string, ctx Context) string {
+	switch ctx {
+	case ContextHTMLText, ContextHTMLAttr:
+		return template.HTMLEscapeString(s)
+	case ContextURLQuery:
+		return url.QueryEscape(s)
+	case ContextJSString:
+		return template.JSEscapeString(s)
+	case ContextCSSValue:
+		return CSSEscape(s)
+	case ContextXMLText:
+		return XMLEscape(s)
+	}
+	return ""
+}
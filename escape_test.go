@@ -0,0 +1,28 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var escapeTests = []struct {
+	input    string
+	ctx      Context
+	expected string
+}{
+	{`<b>"Tom" & 'Jerry'</b>`, ContextHTMLText, `&lt;b&gt;&#34;Tom&#34; &amp; &#39;Jerry&#39;&lt;/b&gt;`},
+	{`" onclick="alert(1)`, ContextHTMLAttr, `&#34; onclick=&#34;alert(1)`},
+	{"a b&c=d/é", ContextURLQuery, `a+b%26c%3Dd%2F%C3%A9`},
+	{`';alert(1);//</script>`, ContextJSString, `\';alert(1);//\u003C/script\u003E`},
+	{"} body {", ContextCSSValue, `\}\ body\ \{`},
+	{`<x a="1">&`, ContextXMLText, `&lt;x a=&quot;1&quot;&gt;&amp;`},
+	{"text", Context(99), ``},
+}
+
+func TestEscape(t *testing.T) {
+	for _, test := range escapeTests {
+		output := Escape(test.input, test.ctx)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package sanitize
+
+import (
+	"strings"
+
+	parser "golang.org/x/net/html"
+)
+
+// Link is a link found in html by ExtractLinks.
+type Link struct {
+	// URL is the normalised href of the link.
+	URL string
+
+	// Text is the text of the link, with whitespace collapsed.
+	Text string
+}
+
+// Image is an image found in html by ExtractImages.
+type Image struct {
+	// URL is the normalised src of the image.
+	URL string
+
+	// Alt is the alternative text of the image.
+	Alt string
+}
+
+// The policy used to check attributes of extracted links and images, using the url rules of HTMLAllowing.
+var extractPolicy = &Policy{
+	Attributes:    []string{"href", "src", "alt"},
+	URLAttributes: []string{"src"},
+	URLs:          hrefOptions,
+}
+
+// ExtractLinks returns the links in html in document order, for example to preview the sites
+// linked to from user content. Links with urls which HTMLAllowing would remove are not returned.
+func ExtractLinks(html string) []Link {
+	var links []Link
+	var link *Link
+	text := strings.Builder{}
+
+	extractTokens(html, func(t parser.Token) {
+		switch {
+		case t.Type == parser.StartTagToken && t.Data == "a":
+			link = nil
+			if href := attribute(extractPolicy.cleanAttributes(t.Data, t.Attr, nil), "href"); href != "" {
+				link = &Link{URL: href}
+				text.Reset()
+			}
+		case t.Type == parser.EndTagToken && t.Data == "a" && link != nil:
+			link.Text = CollapseWhitespace(text.String())
+			links = append(links, *link)
+			link = nil
+		case t.Type == parser.TextToken && link != nil:
+			text.WriteString(t.Data)
+		}
+	})
+	return links
+}
+
+// ExtractImages returns the images in html with their alt text in document order, for example
+// to choose an image for an open graph preview. Images with urls which HTMLAllowing would remove are not returned.
+func ExtractImages(html string) []Image {
+	var images []Image
+	extractTokens(html, func(t parser.Token) {
+		if (t.Type == parser.StartTagToken || t.Type == parser.SelfClosingTagToken) && t.Data == "img" {
+			t.Attr = extractPolicy.cleanAttributes(t.Data, t.Attr, nil)
+			if src := attribute(t.Attr, "src"); src != "" {
+				images = append(images, Image{URL: src, Alt: attribute(t.Attr, "alt")})
+			}
+		}
+	})
+	return images
+}
+
+// extractTokens calls f for each token in html, skipping the contents of elements such as script
+// which HTMLAllowing removes.
+func extractTokens(html string, f func(parser.Token)) {
+	tokenizer := parser.NewTokenizer(strings.NewReader(html))
+
+	// Elements removed with their content, innermost last
+	var ignore []string
+	for tokenizer.Next() != parser.ErrorToken {
+		token := tokenizer.Token()
+
+		// The tokenizer reads the content of raw text elements as text even if the tag is self closing
+		if token.Type == parser.SelfClosingTagToken && includes(rawTextTags, token.Data) {
+			token.Type = parser.StartTagToken
+		}
+
+		switch {
+		case len(ignore) > 0:
+			if token.Type == parser.StartTagToken || token.Type == parser.SelfClosingTagToken {
+				ignore = ignoreElement(ignore, token.Data)
+			} else if token.Type == parser.EndTagToken {
+				ignore = closeIgnored(ignore, token.Data)
+			}
+		case token.Type == parser.StartTagToken && includes(ignoreTags, token.Data):
+			ignore = ignoreElement(ignore, token.Data)
+		default:
+			f(token)
+		}
+	}
+}
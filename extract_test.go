@@ -0,0 +1,44 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	html := `<p>See <a href="HTTPS://Example.com/a b">the <b>docs</b>
+	here</a>, <a href="javascript:alert(1)">evil</a>, <a href="/local">local</a> and <a name="x">anchor</a>.</p>
+	<script><a href="https://hidden.com">x</a></script><a href="mailto:me@example.com">mail</a>
+	<object><object></object><a href="https://nested.com">hidden</a></object>`
+	expected := []Link{
+		{URL: "https://example.com/a%20b", Text: "the docs here"},
+		{URL: "/local", Text: "local"},
+		{URL: "mailto:me@example.com", Text: "mail"},
+	}
+	links := ExtractLinks(html)
+	if len(links) != len(expected) {
+		t.Fatalf("ExtractLinks(%q) = %v, want %v", html, links, expected)
+	}
+	for i, l := range links {
+		if l != expected[i] {
+			t.Fatalf("ExtractLinks(%q) = %v, want %v", html, links, expected)
+		}
+	}
+}
+
+func TestExtractImages(t *testing.T) {
+	html := `<img src="https://example.com/a.png" alt="A cat"><img src="javascript:alert(1)" alt="evil">
+	<p><img src="/b.jpg"/></p><img alt="no src"><img src="data:image/png;base64,AAAA" alt="data">`
+	expected := []Image{
+		{URL: "https://example.com/a.png", Alt: "A cat"},
+		{URL: "/b.jpg"},
+	}
+	images := ExtractImages(html)
+	if len(images) != len(expected) {
+		t.Fatalf("ExtractImages(%q) = %v, want %v", html, images, expected)
+	}
+	for i, img := range images {
+		if img != expected[i] {
+			t.Fatalf("ExtractImages(%q) = %v, want %v", html, images, expected)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package sanitize
+
+import (
+	"strings"
+
+	parser "golang.org/x/net/html"
+)
+
+// Elements which start foreign content, in which tag and attribute names are case sensitive.
+var foreignTags = []string{"svg", "math"}
+
+// Elements inside foreign content whose content is html, such as foreignObject in svg.
+var integrationTags = []string{"foreignObject", "annotation-xml", "desc", "title"}
+
+// The case of svg element names, which the tokenizer lowercases.
+var svgTagNames = caseMap(
+	"altGlyph", "altGlyphDef", "altGlyphItem", "animateColor", "animateMotion", "animateTransform", "clipPath",
+	"feBlend", "feColorMatrix", "feComponentTransfer", "feComposite", "feConvolveMatrix", "feDiffuseLighting",
+	"feDisplacementMap", "feDistantLight", "feDropShadow", "feFlood", "feFuncA", "feFuncB", "feFuncG", "feFuncR",
+	"feGaussianBlur", "feImage", "feMerge", "feMergeNode", "feMorphology", "feOffset", "fePointLight",
+	"feSpecularLighting", "feSpotLight", "feTile", "feTurbulence", "foreignObject", "glyphRef",
+	"linearGradient", "radialGradient", "textPath",
+)
+
+// The case of svg and mathml attribute names, which the tokenizer lowercases.
+var foreignAttributeNames = caseMap(
+	"attributeName", "attributeType", "baseFrequency", "baseProfile", "calcMode", "clipPathUnits", "definitionURL",
+	"diffuseConstant", "edgeMode", "filterUnits", "glyphRef", "gradientTransform", "gradientUnits", "kernelMatrix",
+	"kernelUnitLength", "keyPoints", "keySplines", "keyTimes", "lengthAdjust", "limitingConeAngle", "markerHeight",
+	"markerUnits", "markerWidth", "maskContentUnits", "maskUnits", "numOctaves", "pathLength", "patternContentUnits",
+	"patternTransform", "patternUnits", "pointsAtX", "pointsAtY", "pointsAtZ", "preserveAlpha", "preserveAspectRatio",
+	"primitiveUnits", "refX", "refY", "repeatCount", "repeatDur", "requiredExtensions", "requiredFeatures",
+	"specularConstant", "specularExponent", "spreadMethod", "startOffset", "stdDeviation", "stitchTiles",
+	"surfaceScale", "systemLanguage", "tableValues", "targetX", "targetY", "textLength", "viewBox", "viewTarget",
+	"xChannelSelector", "yChannelSelector", "zoomAndPan",
+)
+
+// The namespaces which may be declared with xmlns attributes in foreign content.
+var foreignNamespaces = []string{
+	"http://www.w3.org/2000/svg",
+	"http://www.w3.org/1998/Math/MathML",
+	"http://www.w3.org/1999/xlink",
+	"http://www.w3.org/XML/1998/namespace",
+}
+
+// caseMap returns a map from the lowercase form of each name to the name.
+func caseMap(names ...string) map[string]string {
+	m := make(map[string]string, len(names))
+	for _, name := range names {
+		m[strings.ToLower(name)] = name
+	}
+	return m
+}
+
+// foreignContent tracks the svg and math elements kept by a policy, so that the names of elements
+// and attributes inside them may be written in the case the tokenizer removed.
+type foreignContent struct {
+	// The kept elements which change the content type, innermost last, with whether their content is foreign
+	open []foreignElement
+}
+
+type foreignElement struct {
+	name    string
+	foreign bool
+}
+
+// inside reports whether the current token is in foreign content.
+func (f *foreignContent) inside() bool {
+	return len(f.open) > 0 && f.open[len(f.open)-1].foreign
+}
+
+// name returns the name of an element in the case used in svg, if inside an svg or math element.
+func (f *foreignContent) name(tag string) string {
+	if len(f.open) > 0 {
+		if name, ok := svgTagNames[tag]; ok {
+			return name
+		}
+	}
+	return tag
+}
+
+// attributes returns the attributes of an element with names in the case used in svg and mathml,
+// if the element is inside foreign content or starts it. The tokenizer does not set the namespace of attributes,
+// so namespaced attributes such as xlink:href are matched by their qualified name.
+func (f *foreignContent) attributes(tag string, attributes []parser.Attribute) []parser.Attribute {
+	if !f.inside() && !includes(foreignTags, tag) {
+		return attributes
+	}
+	for i, a := range attributes {
+		if name, ok := foreignAttributeNames[a.Key]; ok {
+			attributes[i].Key = name
+		}
+	}
+	return attributes
+}
+
+// start records a start tag kept by the policy.
+func (f *foreignContent) start(tag string) {
+	switch {
+	case includes(foreignTags, tag):
+		f.open = append(f.open, foreignElement{name: tag, foreign: true})
+	case f.inside() && includes(integrationTags, tag):
+		f.open = append(f.open, foreignElement{name: tag, foreign: false})
+	}
+}
+
+// end records an end tag kept by the policy.
+func (f *foreignContent) end(tag string) {
+	for i := len(f.open) - 1; i >= 0; i-- {
+		if f.open[i].name == tag {
+			f.open = f.open[:i]
+			return
+		}
+	}
+}
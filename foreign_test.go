@@ -0,0 +1,34 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var foreignTests = []Test{
+	{`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><linearGradient id="g" gradientUnits="userSpaceOnUse"></linearGradient><path d="M0 0L10 10"/></svg>`,
+		`<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg"><linearGradient id="g" gradientUnits="userSpaceOnUse"></linearGradient><path d="M0 0L10 10"/></svg>`},
+	{`<SVG VIEWBOX="0 0 1 1"><CLIPPATH></CLIPPATH></SVG>`, `<svg viewBox="0 0 1 1"><clipPath></clipPath></svg>`},
+	{`<svg xmlns="http://example.com/ns" xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="https://example.com/">link</a></svg>`,
+		`<svg xmlns:xlink="http://www.w3.org/1999/xlink"><a xlink:href="https://example.com/">link</a></svg>`},
+	{`<svg><a xlink:href="javascript:alert(1)">link</a><a xlink:href="java&#x09;script:alert(1)">link</a></svg>`, `<svg><a>link</a><a>link</a></svg>`},
+	{`<svg><text xml:lang="en-GB">colour</text><text xml:lang="not a lang!">x</text></svg>`, `<svg><text xml:lang="en-GB">colour</text><text>x</text></svg>`},
+	{`<svg><animate attributeName="href" values="javascript:alert(1)"/></svg>`, `<svg><animate attributeName="href"/></svg>`},
+	{`<svg><foreignObject><p viewBox="x">html</p></foreignObject></svg><p viewbox="x">html</p>`,
+		`<svg><foreignObject><p>html</p></foreignObject></svg><p>html</p>`},
+	{`<math><mi definitionURL="https://example.com/">x</mi></math>`, `<math><mi definitionURL="https://example.com/">x</mi></math>`},
+	{`<linearGradient></linearGradient>`, ``},
+}
+
+func TestForeignContent(t *testing.T) {
+	p := &Policy{
+		Tags:       []string{"svg", "path", "linearGradient", "clipPath", "a", "text", "animate", "foreignObject", "p", "math", "mi"},
+		Attributes: []string{"viewBox", "xmlns", "xmlns:xlink", "id", "gradientUnits", "d", "xlink:href", "xml:lang", "attributeName", "values", "definitionURL"},
+		URLs:       URLOptions{Schemes: []string{"https"}},
+	}
+	for _, test := range foreignTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package sanitize
+
+import (
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	parser "golang.org/x/net/html"
+)
+
+// fuzzPolicy allows the default tags and attributes, with every output rule enabled.
+func fuzzPolicy() *Policy {
+	return &Policy{
+		Tags:               append([]string{"li", "td", "tr", "table"}, defaultTags...),
+		Attributes:         defaultAttributes,
+		URLs:               hrefOptions,
+		RemoveEmpty:        true,
+		Containment:        true,
+		CollapseFormatting: true,
+		HeadingIDs:         true,
+		Compat:             CompatLatest,
+	}
+}
+
+// addCorpus adds the inputs of the golden files and some malformed html to the seed corpus.
+func addCorpus(f *testing.F) {
+	inputs, _ := filepath.Glob(filepath.Join("testdata", "golden", "*.html"))
+	for _, input := range inputs {
+		data, err := os.ReadFile(input)
+		if err != nil {
+			f.Fatalf("golden read error: %s", err)
+		}
+		for _, c := range strings.Split(string(data), goldenSeparator) {
+			f.Add(c)
+		}
+	}
+	for _, s := range []string{"", "<", "</", "<a", "<a href", "<!--", "<![CDATA[", "<!DOCTYPE", "&#", "&#x", "<h1><h1></h1>", "\xff\xfe<\x00b>"} {
+		f.Add(s)
+	}
+}
+
+func FuzzSanitize(f *testing.F) {
+	addCorpus(f)
+	policies := []*Policy{{Tags: defaultTags, Attributes: defaultAttributes, URLs: hrefOptions}, fuzzPolicy()}
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, p := range policies {
+			output, err := p.Sanitize(s)
+			if err != nil {
+				t.Fatalf("Sanitize error for %q: %s", s, err)
+			}
+
+			// The output must contain only allowed tags, without event handlers or scripts in attributes
+			tokenizer := parser.NewTokenizer(strings.NewReader(output))
+			for tokenizer.Next() != parser.ErrorToken {
+				token := tokenizer.Token()
+				if token.Type == parser.TextToken || token.Type == parser.CommentToken {
+					continue
+				}
+				if !includes(p.Tags, token.Data) {
+					t.Fatalf("Sanitize kept tag %q for %q: %q", token.Data, s, output)
+				}
+				for _, a := range token.Attr {
+					if isEventHandler(a.Key) || illegalAttr.MatchString(unobfuscate(a.Val)) {
+						t.Fatalf("Sanitize kept attribute %q for %q: %q", a.Key, s, output)
+					}
+				}
+			}
+		}
+	})
+}
+
+func FuzzHTML(f *testing.F) {
+	addCorpus(f)
+	f.Fuzz(func(t *testing.T, s string) {
+		output := HTML(s)
+		if strings.ContainsAny(output, "<>") {
+			t.Fatalf("HTML kept markup for %q: %q", s, output)
+		}
+		output = Text(s)
+		if html.UnescapeString(output) != output {
+			t.Fatalf("Text kept entities for %q: %q", s, output)
+		}
+	})
+}
+
+func FuzzPath(f *testing.F) {
+	for _, test := range legacyPathTests {
+		f.Add(test.input)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		output := Path(s)
+		if illegalPath.MatchString(output) || includes(strings.Split(output, "/"), "..") {
+			t.Fatalf("Path kept illegal characters for %q: %q", s, output)
+		}
+		output = Name(s)
+		if illegalName.MatchString(output) || strings.Contains(output, "/") || output == ".." {
+			t.Fatalf("Name kept illegal characters for %q: %q", s, output)
+		}
+	})
+}
+
+// Deeply nested and unbalanced html must be sanitized in linear time, these inputs took minutes
+// when each end tag searched all open elements.
+func TestDeepNesting(t *testing.T) {
+	n := 20000
+	inputs := []string{
+		strings.Repeat("<b>", n),
+		strings.Repeat("<div>", n) + strings.Repeat("</p>", n),
+		strings.Repeat("<i>", n) + strings.Repeat("<b>", n) + strings.Repeat("</i>", n),
+		strings.Repeat("<div>", n) + strings.Repeat("</li>", n),
+		strings.Repeat("<h1>", n),
+		strings.Repeat("<h1>x</h1>", n),
+	}
+	p := fuzzPolicy()
+	for _, input := range inputs {
+		if _, err := p.Sanitize(input); err != nil {
+			t.Fatalf("Sanitize error for %q: %s", input[:10], err)
+		}
+	}
+}
+
+func FuzzStripQuotedReply(f *testing.F) {
+	for _, test := range stripQuotedReplyTests {
+		f.Add(test.input)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		output := StripQuotedReply(s)
+		if _, err := fuzzPolicy().Sanitize(output); err != nil {
+			t.Fatalf("StripQuotedReply output could not be sanitized for %q: %v", s, err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package sanitize
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Run go test -update to rewrite the golden files after an intended change in output.
+// Output at Compat1 must not change, add a CompatLevel for changes which alter it instead.
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// Cases in golden files are separated by a line of dashes.
+const goldenSeparator = "\n-----\n"
+
+// Golden files for each compat level, next to the input file.
+var goldenLevels = map[CompatLevel]string{
+	Compat1: ".golden",
+	Compat2: ".compat2.golden",
+	Compat3: ".compat3.golden",
+	Compat4: ".compat4.golden",
+}
+
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "golden", "*.html"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("golden inputs not found: %v", err)
+	}
+
+	for _, input := range inputs {
+		data, err := os.ReadFile(input)
+		if err != nil {
+			t.Fatalf("golden read error: %s", err)
+		}
+		cases := strings.Split(strings.TrimSuffix(string(data), "\n"), goldenSeparator)
+
+		for level, suffix := range goldenLevels {
+			outputs := make([]string, len(cases))
+			for i, c := range cases {
+				output, err := Sanitize(c, WithCompat(level))
+				if err != nil {
+					output = "error: " + err.Error()
+				}
+				outputs[i] = output
+			}
+
+			golden := strings.TrimSuffix(input, ".html") + suffix
+			if *update {
+				err = os.WriteFile(golden, []byte(strings.Join(outputs, goldenSeparator)+"\n"), 0644)
+				if err != nil {
+					t.Fatalf("golden write error: %s", err)
+				}
+				continue
+			}
+
+			data, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("golden read error: %s", err)
+			}
+			expected := strings.Split(strings.TrimSuffix(string(data), "\n"), goldenSeparator)
+			if len(expected) != len(cases) {
+				t.Fatalf("golden %s has %d cases, expected %d", golden, len(expected), len(cases))
+			}
+			for i, c := range cases {
+				if outputs[i] != expected[i] {
+					t.Fatalf("golden %s case %d"+Format, golden, i+1, c, expected[i], outputs[i])
+				}
+			}
+		}
+	}
+}
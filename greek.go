@@ -0,0 +1,55 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Greek letters are transliterated following ELOT 743 as used for greek passports.
+// Accented letters are transliterated by their base letter.
+var greekLetters = withCapitals(map[rune]string{
+	'α': "a",
+	'β': "v",
+	'γ': "g",
+	'δ': "d",
+	'ε': "e",
+	'ζ': "z",
+	'η': "i",
+	'θ': "th",
+	'ι': "i",
+	'κ': "k",
+	'λ': "l",
+	'μ': "m",
+	'ν': "n",
+	'ξ': "x",
+	'ο': "o",
+	'π': "p",
+	'ρ': "r",
+	'σ': "s",
+	'ς': "s",
+	'τ': "t",
+	'υ': "y",
+	'φ': "f",
+	'χ': "ch",
+	'ψ': "ps",
+	'ω': "o",
+})
+
+// Pairs of greek letters which are transliterated together.
+var greekDigraphs = strings.NewReplacer(
+	"ου", "ou", "ού", "ou", "Ου", "Ou", "Ού", "Ou", "ΟΥ", "OU",
+	"αυ", "av", "αύ", "av", "Αυ", "Av", "Αύ", "Av", "ΑΥ", "AV",
+	"ευ", "ev", "εύ", "ev", "Ευ", "Ev", "Εύ", "Ev", "ΕΥ", "EV",
+	"ηυ", "iv", "ηύ", "iv", "Ηυ", "Iv", "ΗΥ", "IV",
+	"γγ", "ng", "γκ", "gk", "γξ", "nx", "γχ", "nch",
+)
+
+// greekDigraphsReplace transliterates greek digraphs in s, if it contains any greek letters.
+func greekDigraphsReplace(s string) string {
+	for _, r := range s {
+		if unicode.Is(unicode.Greek, r) {
+			return greekDigraphs.Replace(s)
+		}
+	}
+	return s
+}
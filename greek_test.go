@@ -0,0 +1,23 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var greekTests = []Test{
+	{"Ελληνικά", `ellinika`},
+	{"Αθήνα και Θεσσαλονίκη", `athina-kai-thessaloniki`},
+	{"Ψυχή", `psychi`},
+	{"Ουρανός, Ευρώπη", `ouranos-evropi`},
+	{"Άγγελος", `angelos`},
+	{"ΚΑΛΗΜΕΡΑ ΟΔΥΣΣΕΑ", `kalimera-odyssea`},
+}
+
+func TestGreek(t *testing.T) {
+	for _, test := range greekTests {
+		output := Path(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
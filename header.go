@@ -0,0 +1,74 @@
+package sanitize
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Replace line breaks in header values with a space, so that words are not joined
+var headerBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// Header makes a string safe to use as an HTTP or email header value, so that user input
+// cannot inject additional headers. Line breaks are replaced by spaces, other control characters
+// apart from tab are removed, and whitespace is trimmed from either end.
+func Header(s string) string {
+	s = headerBreaks.Replace(s)
+	s = strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || r == '\u2028' || r == '\u2029' {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// LogLine makes a string safe to write to a log on a single line, so that user input cannot
+// forge log entries or hide text. Line breaks, control characters and invisible characters
+// such as bidi overrides are escaped in the same way as go string literals, for example \n or \u202e.
+// Backslashes are left unchanged.
+func LogLine(s string) string {
+	if !strings.ContainsFunc(s, escapeInLog) {
+		return s
+	}
+
+	b := strings.Builder{}
+	for _, r := range s {
+		if escapeInLog(r) {
+			quoted := strconv.QuoteRuneToASCII(r)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// escapeInLog reports whether r should be escaped in a log line.
+func escapeInLog(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029' || r == unicode.ReplacementChar || isInvisible(r)
+}
+
+// Punctuation allowed in http tokens by RFC 7230, as well as letters and digits
+const tokenPunctuation = "!#$%&'*+-.^_`|~"
+
+// HTTPToken removes characters which are not allowed in an http token by RFC 7230, such as
+// a header name, method or parameter name, leaving ascii letters, digits and !#$%&'*+-.^_`|~.
+// It may return an empty string.
+func HTTPToken(s string) string {
+	return keep(s, func(r rune) bool {
+		return isASCIILetter(r) || isASCIIDigit(r) || strings.ContainsRune(tokenPunctuation, r)
+	})
+}
+
+// CookieValue removes characters which are not allowed in a cookie value by RFC 6265, so that
+// it may be used in a Set-Cookie header. Control characters, whitespace, non-ascii characters,
+// double quotes, commas, semicolons and backslashes are removed, which may leave an empty string.
+func CookieValue(s string) string {
+	return keep(s, func(r rune) bool {
+		return r > 0x20 && r < 0x7f && r != '"' && r != ',' && r != ';' && r != '\\'
+	})
+}
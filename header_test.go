@@ -0,0 +1,76 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var headerTests = []Test{
+	{"text/html", `text/html`},
+	{"value\r\nSet-Cookie: session=1", `value Set-Cookie: session=1`},
+	{"subject\nBcc: victim@example.com", `subject Bcc: victim@example.com`},
+	{"  tab\tseparated\x00 ", "tab\tseparated"},
+	{"line\u2028separator", `lineseparator`},
+	{"Grüße aus Köln", `Grüße aus Köln`},
+}
+
+func TestHeader(t *testing.T) {
+	for _, test := range headerTests {
+		output := Header(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var logLineTests = []Test{
+	{"user logged in", `user logged in`},
+	{"bob\n2024-01-01 INFO admin logged in", `bob\n2024-01-01 INFO admin logged in`},
+	{"carriage\rreturn\ttab", `carriage\rreturn\ttab`},
+	{"escape \x1b[31mred", `escape \x1b[31mred`},
+	{"bidi \u202eevil", `bidi \u202eevil`},
+	{"invalid \xff utf8", `invalid \ufffd utf8`},
+	{`back\slash`, `back\slash`},
+	{"café 🚀", `café 🚀`},
+}
+
+func TestLogLine(t *testing.T) {
+	for _, test := range logLineTests {
+		output := LogLine(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var httpTokenTests = []Test{
+	{"X-Custom-Header", `X-Custom-Header`},
+	{"Bad Header: value\r\nInjected", `BadHeadervalueInjected`},
+	{"token!#$%&'*+-.^_`|~09", "token!#$%&'*+-.^_`|~09"},
+	{"(comment)\"quoted\"/path?=é", `commentquotedpath`},
+}
+
+func TestHTTPToken(t *testing.T) {
+	for _, test := range httpTokenTests {
+		output := HTTPToken(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var cookieValueTests = []Test{
+	{"abc123", `abc123`},
+	{"session=1; Path=/; HttpOnly", `session=1Path=/HttpOnly`},
+	{"quoted \"value\", with\\slash", `quotedvaluewithslash`},
+	{"line\r\nSet-Cookie: admin=1", `lineSet-Cookie:admin=1`},
+	{"café\x7f", `caf`},
+}
+
+func TestCookieValue(t *testing.T) {
+	for _, test := range cookieValueTests {
+		output := CookieValue(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package sanitize
+
+import (
+	"io"
+	"strings"
+
+	parser "golang.org/x/net/html"
+)
+
+// Heading elements which are given ids by Policy.HeadingIDs.
+var headingTags = []string{"h1", "h2", "h3", "h4", "h5", "h6"}
+
+// Heading is a heading in an html document, as returned by TOC.
+type Heading struct {
+	// Level is the heading level, from 1 for h1 to 6 for h6.
+	Level int
+
+	// Text is the text of the heading, with whitespace collapsed.
+	Text string
+
+	// ID is the id of the heading, which may be used as a url fragment to link to it.
+	ID string
+}
+
+// TOC returns the headings in html in document order, for use in a table of contents.
+// Headings without an id are given the id which Policy.HeadingIDs would generate, so TOC may be
+// called on html before or after sanitizing it. The nesting of headings is given by their Level.
+func TOC(html string) []Heading {
+	var tokens []outputToken
+	tokenizer := parser.NewTokenizer(strings.NewReader(html))
+	for tokenizer.Next() != parser.ErrorToken {
+		tokens = append(tokens, outputToken{Token: tokenizer.Token()})
+	}
+	if tokenizer.Err() != io.EOF {
+		return nil
+	}
+
+	var headings []Heading
+	for i, t := range headingIDs(tokens) {
+		if t.Type == parser.StartTagToken && includes(headingTags, t.Data) {
+			headings = append(headings, Heading{
+				Level: int(t.Data[1] - '0'),
+				Text:  headingText(tokens[i+1:], t.Data),
+				ID:    attribute(t.Attr, "id"),
+			})
+		}
+	}
+	return headings
+}
+
+// headingIDs sets an id made from the text of each heading without one, using Path and appending -2, -3 and so on
+// to ids already used in the document. The tokens are modified in place.
+func headingIDs(tokens []outputToken) []outputToken {
+	used := make(map[string]bool)
+	for _, t := range tokens {
+		if id := attribute(t.Attr, "id"); id != "" {
+			used[id] = true
+		}
+	}
+
+	suffixes := make(map[string]int)
+	for i, t := range tokens {
+		if t.Type != parser.StartTagToken || !includes(headingTags, t.Data) || attribute(t.Attr, "id") != "" {
+			continue
+		}
+		id := headingID(headingText(tokens[i+1:], t.Data), used, suffixes)
+		tokens[i].Attr = append(t.Attr, parser.Attribute{Key: "id", Val: id})
+	}
+	return tokens
+}
+
+// headingID returns a unique id for a heading with text, and adds it to used.
+// The next suffix to try for each slug is kept in suffixes, so that documents with many headings
+// with the same text do not try every suffix again for each heading.
+func headingID(text string, used map[string]bool, suffixes map[string]int) string {
+	// Ids are used as url fragments, so avoid the path separators kept by Path
+	text = baseNameSeparators.ReplaceAllString(text, " ")
+	slug := Path(text)
+	if strings.Trim(slug, "./-") == "" {
+		slug = "heading"
+	}
+	start := suffixes[slug]
+	if start < 2 {
+		start = 2
+	}
+	id, suffix := uniqueSlug(slug, start, func(s string) bool { return used[s] })
+	if suffix > 0 {
+		suffixes[slug] = suffix + 1
+	}
+	used[id] = true
+	return id
+}
+
+// headingText returns the text of the tokens up to the end tag for a heading, with whitespace collapsed.
+// A heading cannot contain another heading, so the text also ends at the start of the next heading.
+func headingText(tokens []outputToken, tag string) string {
+	b := strings.Builder{}
+	for _, t := range tokens {
+		if t.Type == parser.EndTagToken && t.Data == tag || t.Type == parser.StartTagToken && includes(headingTags, t.Data) {
+			break
+		}
+		if t.Type == parser.TextToken {
+			b.WriteString(t.Data)
+		}
+	}
+	return CollapseWhitespace(b.String())
+}
+
+// attribute returns the value of the attribute key, or an empty string if it is not set.
+func attribute(attributes []parser.Attribute, key string) string {
+	for _, a := range attributes {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var headingIDTests = []Test{
+	{`<h1>Hello World</h1>`, `<h1 id="hello-world">Hello World</h1>`},
+	{`<h2>Intro</h2><p>text</p><h2>Intro</h2><h3>Intro</h3>`, `<h2 id="intro">Intro</h2><p>text</p><h2 id="intro-2">Intro</h2><h3 id="intro-3">Intro</h3>`},
+	{`<h2>Using <b>bold</b> text</h2>`, `<h2 id="using-bold-text">Using <b>bold</b> text</h2>`},
+	{`<h2 id="custom">Custom</h2><h3>Custom</h3>`, `<h2 id="custom">Custom</h2><h3 id="custom-2">Custom</h3>`},
+	{`<h3>Files / Folders.</h3>`, `<h3 id="files-folders">Files / Folders.</h3>`},
+	{`<h4>Café & Crème</h4>`, `<h4 id="cafe-creme">Café &amp; Crème</h4>`},
+	{`<h5>!!!</h5><h5></h5>`, `<h5 id="heading">!!!</h5><h5 id="heading-2"></h5>`},
+	{`<h1 onclick="evil()">Clicked<script>x</script></h1>`, `<h1 id="clicked">Clicked</h1>`},
+}
+
+func TestHeadingIDs(t *testing.T) {
+	p := &Policy{Tags: []string{"h1", "h2", "h3", "h4", "h5", "b", "p"}, Attributes: []string{"id"}, HeadingIDs: true}
+	for _, test := range headingIDTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestTOC(t *testing.T) {
+	html := `<h1>Guide</h1><p>intro</p><h2 id="setup">Setting <i>up</i></h2><h3>Notes</h3><h2>Usage</h2><h3>Notes</h3>`
+	expected := []Heading{
+		{Level: 1, Text: "Guide", ID: "guide"},
+		{Level: 2, Text: "Setting up", ID: "setup"},
+		{Level: 3, Text: "Notes", ID: "notes"},
+		{Level: 2, Text: "Usage", ID: "usage"},
+		{Level: 3, Text: "Notes", ID: "notes-2"},
+	}
+	headings := TOC(html)
+	if len(headings) != len(expected) {
+		t.Fatalf("TOC(%q) = %v, want %v", html, headings, expected)
+	}
+	for i, h := range headings {
+		if h != expected[i] {
+			t.Fatalf("TOC(%q) = %v, want %v", html, headings, expected)
+		}
+	}
+
+	// The ids match those added when sanitizing
+	p := &Policy{Tags: []string{"h1", "h2", "h3", "p", "i"}, Attributes: []string{"id"}, HeadingIDs: true}
+	sanitized, err := p.Sanitize(html)
+	if err != nil {
+		t.Fatalf("Sanitize(%q) error: %s", html, err)
+	}
+	for i, h := range TOC(sanitized) {
+		if h != headings[i] {
+			t.Fatalf("TOC(%q) = %v, want %v", sanitized, h, headings[i])
+		}
+	}
+
+	if len(TOC("no headings")) != 0 {
+		t.Fatalf("TOC returned headings for plain text")
+	}
+}
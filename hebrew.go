@@ -0,0 +1,56 @@
+package sanitize
+
+// Hebrew letters are romanized following common israeli practice for names and signs,
+// with vowel points transliterated where present.
+// Applications may override these with RegisterTransliterations.
+var hebrewLetters = TransliterationMap{
+	'א': "a",
+	'ב': "b",
+	'ג': "g",
+	'ד': "d",
+	'ה': "h",
+	'ו': "v",
+	'ז': "z",
+	'ח': "ch",
+	'ט': "t",
+	'י': "y",
+	'ך': "k",
+	'כ': "k",
+	'ל': "l",
+	'ם': "m",
+	'מ': "m",
+	'ן': "n",
+	'נ': "n",
+	'ס': "s",
+	'ע': "a",
+	'ף': "f",
+	'פ': "p",
+	'ץ': "ts",
+	'צ': "ts",
+	'ק': "k",
+	'ר': "r",
+	'ש': "sh",
+	'ת': "t",
+
+	// Vowel points
+	'ְ': "",
+	'ֱ': "e",
+	'ֲ': "a",
+	'ֳ': "o",
+	'ִ': "i",
+	'ֵ': "e",
+	'ֶ': "e",
+	'ַ': "a",
+	'ָ': "a",
+	'ֹ': "o",
+	'ֺ': "o",
+	'ֻ': "u",
+	'ּ': "",
+	'ׁ': "",
+	'ׂ': "",
+
+	// Punctuation
+	'־': "-",
+	'׳': "",
+	'״': "",
+}
@@ -0,0 +1,68 @@
+package sanitize
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrHostname is returned by Hostname if no valid hostname can be made from the input.
+var ErrHostname = errors.New("sanitize: invalid hostname")
+
+// The maximum lengths of a dns label and name, in ascii
+const (
+	maxLabelLength    = 63
+	maxHostnameLength = 253
+)
+
+// Hostname makes a valid dns name from user input such as an organisation name, for example to use
+// as a subdomain. Dots separate labels, other characters which are not letters or digits are replaced with -,
+// labels are lowercased, trimmed of - and shortened to 63 characters, and non-ascii labels are converted
+// to punycode. ErrHostname is returned if the result is empty or longer than 253 characters.
+func Hostname(s string) (string, error) {
+	s = strings.ToLower(norm.NFC.String(strings.TrimSpace(s)))
+
+	var labels []string
+	for _, label := range strings.Split(s, ".") {
+		label = strings.Trim(strings.Join(strings.FieldsFunc(label, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+		}), "-"), "-")
+		if label = hostnameLabel(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	host := strings.Join(labels, ".")
+	if host == "" || len(host) > maxHostnameLength {
+		return "", ErrHostname
+	}
+	return host, nil
+}
+
+// hostnameLabel returns a label in ascii, converting it to punycode or removing accents if it cannot be converted,
+// and shortening it to 63 characters.
+func hostnameLabel(label string) string {
+	runes := []rune(label)
+	for len(runes) > 0 {
+		ascii, err := idna.Lookup.ToASCII(string(runes))
+		if err != nil {
+			// Fall back to ascii letters for labels idna does not allow, such as those mixing directions
+			ascii = strings.Trim(strings.Join(strings.FieldsFunc(Accents(string(runes)), func(r rune) bool {
+				return !isASCIILetter(r) && !isASCIIDigit(r)
+			}), "-"), "-")
+			if len(ascii) > maxLabelLength {
+				ascii = strings.TrimRight(ascii[:maxLabelLength], "-")
+			}
+			return strings.ToLower(ascii)
+		}
+		if len(ascii) <= maxLabelLength {
+			return ascii
+		}
+		// Shorten the label before it is encoded, so that punycode is not cut in the middle
+		runes = []rune(strings.TrimRight(string(runes[:len(runes)-1]), "-"))
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var hostnameTests = []Test{
+	{"Acme Corp, Inc.", `acme-corp-inc`},
+	{"  Support.Example.COM  ", `support.example.com`},
+	{"Café Münster", `xn--caf-mnster-d7a4u`},
+	{"東京 Office", `xn---office-gy4k245p`},
+	{"--leading & trailing--", `leading-trailing`},
+	{"a..b...c", `a.b.c`},
+	{"under_score/slash", `under-score-slash`},
+	{strings.Repeat("a", 70) + ".com", strings.Repeat("a", 63) + ".com"},
+	{strings.Repeat("é", 70), `xn--9caaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa`},
+	{"!!! ...", ``},
+}
+
+func TestHostname(t *testing.T) {
+	for _, test := range hostnameTests {
+		output, err := Hostname(test.input)
+		if output != test.expected || (err == nil) != (test.expected != "") {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+		if len(output) > 0 && strings.IndexFunc(output, func(r rune) bool { return r > 127 }) != -1 {
+			t.Fatalf("Hostname(%q) = %q is not ascii", test.input, output)
+		}
+	}
+
+	long := strings.Repeat(strings.Repeat("a", 60)+".", 5)
+	if output, err := Hostname(long); err != ErrHostname {
+		t.Fatalf("Hostname(%q) = %q, want ErrHostname", long, output)
+	}
+}
@@ -0,0 +1,43 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Escapes for characters with special meaning in iCalendar and vCard text values
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\r", `\n`, "\n", `\n`)
+
+// The maximum length in bytes of an iCalendar content line, excluding the line break
+const icalLineLength = 75
+
+// ICalText escapes a string for use as an iCalendar (RFC 5545) or vCard text value, such as an event summary
+// or description. Backslashes, semicolons and commas are escaped with a backslash, line breaks are written as \n,
+// and other control characters apart from tab are removed. Use ICalFold to fold the complete content line.
+func ICalText(s string) string {
+	s = icalEscaper.Replace(s)
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// ICalFold folds an iCalendar content line such as SUMMARY:text into lines of at most 75 bytes,
+// separated by \r\n and a space, without splitting multibyte characters.
+func ICalFold(line string) string {
+	b := strings.Builder{}
+	length := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if length+size > icalLineLength {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
@@ -0,0 +1,42 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var icalTextTests = []Test{
+	{"Team meeting", `Team meeting`},
+	{"Lunch; then coffee, maybe", `Lunch\; then coffee\, maybe`},
+	{`C:\path`, `C:\\path`},
+	{"line one\r\nline two\nthree\rfour", `line one\nline two\nthree\nfour`},
+	{"injected\r\nEND:VEVENT", `injected\nEND:VEVENT`},
+	{"tab\tbell\a", "tab\tbell"},
+}
+
+func TestICalText(t *testing.T) {
+	for _, test := range icalTextTests {
+		output := ICalText(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestICalFold(t *testing.T) {
+	short := "SUMMARY:Team meeting"
+	if output := ICalFold(short); output != short {
+		t.Fatalf(Format, short, short, output)
+	}
+
+	long := "DESCRIPTION:" + strings.Repeat("é", 60)
+	output := ICalFold(long)
+	for _, line := range strings.Split(output, "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf(Format, long, "lines of at most 75 bytes", output)
+		}
+	}
+	if strings.Replace(output, "\r\n ", "", -1) != long {
+		t.Fatalf(Format, long, long, output)
+	}
+}
@@ -0,0 +1,84 @@
+package sanitize
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// words splits s into ascii words for identifiers, after transliterating accents and other scripts with Accents.
+// Words are separated by any character which is not an ascii letter or digit, and by changes of case
+// such as userName or HTTPServer.
+func words(s string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(Accents(s))
+	for i, r := range runes {
+		if !isASCIILetter(r) && !isASCIIDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+
+		// Start a new word at userName and at the S of HTTPServer
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := word[len(word)-1]
+			if unicode.IsLower(prev) || isASCIIDigit(prev) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(prev)) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+// SnakeCase converts s to lowercase words separated by _, such as user_name, transliterating accents
+// and removing other characters.
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+// CamelCase converts s to words joined with the first letter of each word after the first in uppercase,
+// such as userName, transliterating accents and removing other characters.
+func CamelCase(s string) string {
+	b := strings.Builder{}
+	for i, word := range words(s) {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// Identifier returns a valid go identifier made from s in CamelCase, for use in generated code.
+// Identifiers starting with a digit are prefixed with _, and go keywords such as type are suffixed with _.
+// If s contains no letters or digits an empty string is returned.
+func Identifier(s string) string {
+	id := CamelCase(s)
+	if id == "" {
+		return ""
+	}
+	if isASCIIDigit(rune(id[0])) {
+		id = "_" + id
+	}
+	if token.IsKeyword(id) {
+		id += "_"
+	}
+	return id
+}
+
+// EnvVar returns an environment variable name made from s in uppercase words separated by _,
+// such as DATABASE_URL. Leading digits are removed, so that the name starts with a letter,
+// which may leave an empty string.
+func EnvVar(s string) string {
+	name := strings.ToUpper(SnakeCase(s))
+	return strings.TrimLeft(name, "0123456789_")
+}
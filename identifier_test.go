@@ -0,0 +1,76 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var snakeCaseTests = []Test{
+	{"User Name", `user_name`},
+	{"userName", `user_name`},
+	{"HTTPServer URL", `http_server_url`},
+	{"Café — crème brûlée!", `cafe_creme_brulee`},
+	{"version2 Value", `version2_value`},
+	{"already_snake_case", `already_snake_case`},
+	{"", ``},
+}
+
+func TestSnakeCase(t *testing.T) {
+	for _, test := range snakeCaseTests {
+		output := SnakeCase(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var camelCaseTests = []Test{
+	{"User Name", `userName`},
+	{"user_name", `userName`},
+	{"HTTP server-url", `httpServerUrl`},
+	{"Straße", `strasse`},
+	{"Привет мир", `privetMir`},
+}
+
+func TestCamelCase(t *testing.T) {
+	for _, test := range camelCaseTests {
+		output := CamelCase(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var identifierTests = []Test{
+	{"Order Total", `orderTotal`},
+	{"2nd place", `_2ndPlace`},
+	{"type", `type_`},
+	{"Func", `func_`},
+	{"<script>", `script`},
+	{"!!!", ``},
+}
+
+func TestIdentifier(t *testing.T) {
+	for _, test := range identifierTests {
+		output := Identifier(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var envVarTests = []Test{
+	{"Database URL", `DATABASE_URL`},
+	{"api.key", `API_KEY`},
+	{"1st value", `ST_VALUE`},
+	{"éclair count", `ECLAIR_COUNT`},
+	{"123", ``},
+}
+
+func TestEnvVar(t *testing.T) {
+	for _, test := range envVarTests {
+		output := EnvVar(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	parser "golang.org/x/net/html"
+)
+
+// JSONString escapes a string for use inside a quoted json string value, for code which builds json
+// by concatenation. Quotes, backslashes and control characters are escaped, invalid UTF-8 is replaced
+// with U+FFFD, and the line and paragraph separators U+2028 and U+2029 are escaped for javascript.
+// The surrounding quotes are not added.
+func JSONString(s string) string {
+	return jsonString(s, false)
+}
+
+// JSONStringHTML escapes a string like JSONString, and also escapes <, > and & as \u003c, \u003e and \u0026,
+// so that json embedded in html, for example in a script element, cannot close the element.
+func JSONStringHTML(s string) string {
+	return jsonString(s, true)
+}
+
+// jsonString escapes s for json, escaping html characters if escapeHTML is set.
+func jsonString(s string, escapeHTML bool) string {
+	b := strings.Builder{}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+
+		switch {
+		case r == '"':
+			b.WriteString(`\"`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == utf8.RuneError && size == 1:
+			b.WriteString(`\ufffd`)
+		case r < 0x20 || r == 0x2028 || r == 0x2029:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case escapeHTML && (r == '<' || r == '>' || r == '&'):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// The type of script elements kept by Policy.StructuredData.
+const jsonLDType = "application/ld+json"
+
+// isJSONLD reports whether t is the start tag of a json-ld script element.
+func isJSONLD(t parser.Token) bool {
+	if t.Data != "script" {
+		return false
+	}
+	for _, attr := range t.Attr {
+		if attr.Key == "type" && strings.EqualFold(strings.TrimSpace(attr.Val), jsonLDType) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanJSONLD returns the json in a json-ld script element with <, >, & and the line and paragraph separators
+// escaped in strings so that it cannot close the element, or false if it is not valid json.
+func cleanJSONLD(s string) (string, bool) {
+	if !json.Valid([]byte(s)) {
+		return "", false
+	}
+	b := bytes.Buffer{}
+	json.HTMLEscape(&b, []byte(s))
+	return b.String(), true
+}
@@ -0,0 +1,40 @@
+package sanitize
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var jsonStringTests = []Test{
+	{"plain text", `plain text`},
+	{`say "hi"`, `say \"hi\"`},
+	{`back\slash`, `back\\slash`},
+	{"line\nbreak\r\ttab", `line\nbreak\r\ttab`},
+	{"null\x00 and bell\a", `null\u0000 and bell\u0007`},
+	{"invalid\xffutf8", `invalid\ufffdutf8`},
+	{"separators\u2028\u2029", `separators\u2028\u2029`},
+	{"</script><b>&", `</script><b>&`},
+	{"café 🚀", `café 🚀`},
+}
+
+func TestJSONString(t *testing.T) {
+	for _, test := range jsonStringTests {
+		output := JSONString(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+
+		// The result must be a valid json string
+		var decoded string
+		if err := json.Unmarshal([]byte(`"`+output+`"`), &decoded); err != nil {
+			t.Fatalf(Format, test.input, test.expected, err)
+		}
+	}
+
+	input := "</script><b>&"
+	output := JSONStringHTML(input)
+	expected := `\u003c/script\u003e\u003cb\u003e\u0026`
+	if output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+}
@@ -0,0 +1,118 @@
+package sanitize
+
+import "testing"
+
+// Outputs of the legacy api, which must not change as it is reworked on top of Policy and Option.
+var legacyHTMLTests = []struct {
+	input    string
+	text     string // HTML
+	allowed  string // HTMLAllowing with default tags and attributes
+	restrict string // HTMLAllowing with p and a tags and href attributes
+}{
+	{"<p>Hello <b>World</b></p>", "Hello World\n", "<p>Hello <b>World</b></p>", "<p>Hello World</p>"},
+	{"<script>alert(1)</script><p onclick=\"x()\">text</p>", "alert(1)text\n", "<p>text</p>", "<p>text</p>"},
+	{"<a href=\"javascript:alert(1)\">link</a><a href=\"/about\" title=\"About\">about</a>", "linkabout", "<a>link</a><a href=\"/about\" title=\"About\">about</a>", "<a>link</a><a href=\"/about\">about</a>"},
+	{"<img src=\"https://example.com/a.png\" alt=\"A\"><br/><hr>", "\n", "<img src=\"https://example.com/a.png\" alt=\"A\"><br/><hr>", ""},
+	{"<div class=\"x\" style=\"color:red\"><span>a &amp; b</span></div>", "a & b", "<div class=\"x\"><span>a &amp; b</span></div>", "a &amp; b"},
+	{"<iframe src=\"https://example.com\"></iframe><p>after</p>", "after\n", "<p>after</p>", "<p>after</p>"},
+	{"Plain text & \"quotes\" it's", "Plain text & \"quotes\" it's", "Plain text &amp; &#34;quotes&#34; it&#39;s", "Plain text &amp; &#34;quotes&#34; it&#39;s"},
+	{"<ul><li>One<li>Two</ul>", "OneTwo", "<ul><li>One<li>Two</ul>", "OneTwo"},
+	{"<h1 id=\"top\">Title</h1><p>Para one</p><p>Para two<br>line</p>", "TitlePara one\nPara two\nline\n", "<h1 id=\"top\">Title</h1><p>Para one</p><p>Para two<br>line</p>", "Title<p>Para one</p><p>Para twoline</p>"},
+	{"hello<br ><br / ><hr /><hr    >rulers", "hellorulers", "hello<br><br><hr/><hr>rulers", "hellorulers"},
+	{"text<p>inside<p onclick='alert()'/>too", "textinsidetoo", "text<p>inside<p/>too", "text<p>inside<p/>too"},
+	{"<img></IMG SRC=javascript:alert(1)><a href=\"/\"><img src=\"/a.png\"/></a>", "", "<img></img><a href=\"/\"><img src=\"/a.png\"/></a>", "<a href=\"/\"></a>"},
+	{"<a <script>document.write(\"x\");<script/> >", "document.write(\"x\"); ", "<a>document.write(&#34;x&#34;); &gt;", "<a>document.write(&#34;x&#34;); &gt;"},
+	{"<p>Überfluß &amp; “quotes”&nbsp;here</p>", "Überfluß & “quotes” here\n", "<p>Überfluß &amp; “quotes”\u00a0here</p>", "<p>Überfluß &amp; “quotes”\u00a0here</p>"},
+	{"<figure><img src=\"/a.png\" alt=\"A\"><figcaption>Caption</figcaption></figure>", "Caption", "<img src=\"/a.png\" alt=\"A\">Caption", "Caption"},
+	{"<p lang=\"en\" dir=\"ltr\">English</p><blockquote cite=\"https://example.com/\">q</blockquote><time datetime=\"2024-03-01\">1 March</time>", "English\nq1 March", "<p>English</p><blockquote>q</blockquote>1 March", "<p>English</p>q1 March"},
+}
+
+func TestLegacyHTML(t *testing.T) {
+	restrict := [][]string{{"p", "a"}, {"href"}}
+	for _, test := range legacyHTMLTests {
+		output := HTML(test.input)
+		if output != test.text {
+			t.Fatalf(Format, test.input, test.text, output)
+		}
+		output, err := HTMLAllowing(test.input)
+		if err != nil || output != test.allowed {
+			t.Fatalf(Format, test.input, test.allowed, output)
+		}
+		output, err = HTMLAllowing(test.input, restrict...)
+		if err != nil || output != test.restrict {
+			t.Fatalf(Format, test.input, test.restrict, output)
+		}
+		output, err = Sanitize(test.input, WithTags(restrict[0]...), WithAttributes(restrict[1]...))
+		if err != nil || output != test.restrict {
+			t.Fatalf(Format, test.input, test.restrict, output)
+		}
+	}
+}
+
+var legacyPathTests = []struct {
+	input string
+	path  string // Path
+	name  string // Name
+}{
+	{"/Hello World/Ünïcode File.html", "/hello-world/uenicode-file.html", "uenicode-file.html"},
+	{"../../etc/passwd", "/etc/passwd", "passwd"},
+	{"Q&A: why? how!.txt", "q-a-why-how.txt", "q-a-why-how.txt"},
+	{"  trailing space  ", "trailing-space", "trailing-space"},
+	{"C++ & C# (compared)", "c-c-compared", "c-c-compared"},
+	{"Straße_über.PDF", "strasse-ueber.pdf", "strasse-ueber.pdf"},
+	{"", ".", "."},
+	{"/user/test/I am a long url's_-?ASDF@£$%£%^testé.html", "/user/test/i-am-a-long-urls-asdfteste.html", "i-am-a-long-urls-asdfteste.html"},
+}
+
+func TestLegacyPath(t *testing.T) {
+	for _, test := range legacyPathTests {
+		output := Path(test.input)
+		if output != test.path {
+			t.Fatalf(Format, test.input, test.path, output)
+		}
+		output = Slug(test.input, SlugOptions{})
+		if output != test.path {
+			t.Fatalf(Format, test.input, test.path, output)
+		}
+		output = Name(test.input)
+		if output != test.name {
+			t.Fatalf(Format, test.input, test.name, output)
+		}
+		output = FileName(test.input, SlugOptions{})
+		if output != test.name {
+			t.Fatalf(Format, test.input, test.name, output)
+		}
+	}
+}
+
+// Capitals transliterated by earlier versions, which must not change
+var legacyAccentsTests = []struct {
+	input    string
+	accents  string // Accents
+	baseName string // BaseName
+	path     string // Path
+}{
+	{"ÄÖÜ ÆŒ", "AOEUE AEOE", "AOEUE-AEOE", "aeoeue-aeoe"},
+	{"ÜBER", "UEBER", "UEBER", "ueber"},
+	{"ÅSE ØRE ÐÞ Ł ẞ", "AASE OERE DTH L SS", "AASE-OERE-DTH-L-SS", "aase-oere-dth-l-ss"},
+	{"Österreich Über", "OEsterreich UEber", "OEsterreich-UEber", "oesterreich-ueber"},
+	{"ÇÉÑ", "CEN", "CEN", "cen"},
+	{"Überfluß an Döner", "UEberfluss an Doener", "UEberfluss-an-Doener", "ueberfluss-an-doener"},
+}
+
+func TestLegacyAccents(t *testing.T) {
+	for _, test := range legacyAccentsTests {
+		output := Accents(test.input)
+		if output != test.accents {
+			t.Fatalf(Format, test.input, test.accents, output)
+		}
+		output = BaseName(test.input)
+		if output != test.baseName {
+			t.Fatalf(Format, test.input, test.baseName, output)
+		}
+		output = Path(test.input)
+		if output != test.path {
+			t.Fatalf(Format, test.input, test.path, output)
+		}
+	}
+}
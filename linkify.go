@@ -0,0 +1,175 @@
+package sanitize
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LinkifyOptions configures Linkify, and the linking of text when set as Policy.Linkify.
+type LinkifyOptions struct {
+	// URLs sets the checks applied to links found, by default links must be http or https
+	// and hosts with confusable characters are not linked.
+	URLs URLOptions
+
+	// Target sets the target attribute of links if not empty, for example _blank.
+	Target string
+
+	// Rules link other patterns in text, such as mentions or hashtags.
+	Rules []LinkRule
+}
+
+// LinkRule links text matching a pattern, for example @user to /users/user.
+type LinkRule struct {
+	// Pattern matches the text to link, which must not follow a letter or digit.
+	Pattern *regexp.Regexp
+
+	// URL is the link for each match, with $1 and so on replaced by the escaped submatches.
+	// Relative urls are allowed, the url is checked with URL.
+	URL string
+}
+
+// MentionRule returns a rule linking mentions such as @user to url, with $1 replaced by the name.
+func MentionRule(url string) LinkRule {
+	return LinkRule{Pattern: regexp.MustCompile(`@([\pL\pN_]+)`), URL: url}
+}
+
+// HashtagRule returns a rule linking hashtags such as #golang to url, with $1 replaced by the tag.
+func HashtagRule(url string) LinkRule {
+	return LinkRule{Pattern: regexp.MustCompile(`#([\pL\pN_]*\pL[\pL\pN_]*)`), URL: url}
+}
+
+var (
+	// Urls start with a scheme, and end before whitespace, quotes or angle brackets
+	linkifyURL = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"']+`)
+
+	// Emails are matched loosely, and checked with EmailAddress
+	linkifyEmail = regexp.MustCompile(`[\pL\pN._%+-]+@[\pL\pN-]+(\.[\pL\pN-]+)*\.\pL{2,}`)
+
+	// Options used to check links if none are set
+	linkifyURLOptions = URLOptions{Schemes: []string{"http", "https"}, RejectConfusable: true}
+)
+
+// Linkify converts plain text to html, escaping the text and wrapping http and https urls and
+// email addresses in links with rel="nofollow". Urls are checked and normalised with URL and emails
+// with EmailAddress, and are left as text if they are rejected. Trailing punctuation, such as
+// a full stop at the end of a sentence, is not included in links. Text matching opts.Rules
+// is linked after urls and emails.
+func Linkify(s string, opts LinkifyOptions) string {
+	matches := linkifyURL.FindAllStringIndex(s, -1)
+
+	b := strings.Builder{}
+	start := 0
+	for _, m := range matches {
+		end := m[0] + len(trimLinkPunctuation(s[m[0]:m[1]]))
+		b.WriteString(linkifyEmails(s[start:m[0]], opts))
+		b.WriteString(linkifyURLText(s[m[0]:end], opts))
+		start = end
+	}
+	b.WriteString(linkifyEmails(s[start:], opts))
+	return b.String()
+}
+
+// linkifyURLText returns a link for a url, or the escaped url if it is rejected.
+func linkifyURLText(text string, opts LinkifyOptions) string {
+	urlOptions := opts.URLs
+	if len(urlOptions.Schemes) == 0 {
+		urlOptions = linkifyURLOptions
+	}
+	href, err := URL(text, urlOptions)
+	if err != nil {
+		return html.EscapeString(text)
+	}
+	return link(href, text, opts)
+}
+
+// linkifyEmails escapes text, wrapping any email addresses in mailto links.
+func linkifyEmails(s string, opts LinkifyOptions) string {
+	b := strings.Builder{}
+	start := 0
+	for _, m := range linkifyEmail.FindAllStringIndex(s, -1) {
+		b.WriteString(linkifyRules(s[start:m[0]], opts.Rules, opts))
+		if address, err := EmailAddress(s[m[0]:m[1]]); err == nil {
+			b.WriteString(link("mailto:"+address, s[m[0]:m[1]], opts))
+		} else {
+			b.WriteString(html.EscapeString(s[m[0]:m[1]]))
+		}
+		start = m[1]
+	}
+	b.WriteString(linkifyRules(s[start:], opts.Rules, opts))
+	return b.String()
+}
+
+// linkifyRules escapes text, linking matches of the first rule and then the remaining rules in the text between.
+func linkifyRules(s string, rules []LinkRule, opts LinkifyOptions) string {
+	if len(rules) == 0 {
+		return html.EscapeString(s)
+	}
+	rule := rules[0]
+
+	b := strings.Builder{}
+	start := 0
+	for _, m := range rule.Pattern.FindAllStringSubmatchIndex(s, -1) {
+		// Matches must start a word, so that an address like a@b is not a mention
+		if r, _ := utf8.DecodeLastRuneInString(s[:m[0]]); m[0] > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_') {
+			continue
+		}
+
+		href := expandLinkRule(rule.URL, s, m)
+
+		urlOptions := opts.URLs
+		urlOptions.AllowRelative = true
+		if u, err := URL(href, urlOptions); err == nil {
+			b.WriteString(linkifyRules(s[start:m[0]], rules[1:], opts))
+			b.WriteString(link(u, s[m[0]:m[1]], opts))
+			start = m[1]
+		}
+	}
+	b.WriteString(linkifyRules(s[start:], rules[1:], opts))
+	return b.String()
+}
+
+// Submatch references in link rule urls
+var linkRuleRefs = regexp.MustCompile(`\$(\d)`)
+
+// expandLinkRule replaces $1 and so on in template with the escaped submatches of s at m.
+func expandLinkRule(template string, s string, m []int) string {
+	return linkRuleRefs.ReplaceAllStringFunc(template, func(ref string) string {
+		i := int(ref[1]-'0') * 2
+		if i+1 >= len(m) || m[i] < 0 {
+			return ""
+		}
+		return url.PathEscape(s[m[i]:m[i+1]])
+	})
+}
+
+// link returns an html link to href, with escaped text.
+func link(href, text string, opts LinkifyOptions) string {
+	a := `<a href="` + html.EscapeString(href) + `" rel="nofollow"`
+	if opts.Target != "" {
+		a += ` target="` + html.EscapeString(opts.Target) + `"`
+	}
+	return a + ">" + html.EscapeString(text) + "</a>"
+}
+
+// trimLinkPunctuation removes punctuation from the end of a url which is more likely to end the sentence,
+// keeping closing brackets which match an opening bracket in the url.
+func trimLinkPunctuation(u string) string {
+	for len(u) > 0 {
+		last := u[len(u)-1]
+		switch {
+		case strings.IndexByte(".,;:!?*", last) >= 0:
+			u = u[:len(u)-1]
+		case last == ')' && strings.Count(u, "(") < strings.Count(u, ")"):
+			u = u[:len(u)-1]
+		case last == ']' && strings.Count(u, "[") < strings.Count(u, "]"):
+			u = u[:len(u)-1]
+		default:
+			return u
+		}
+	}
+	return u
+}
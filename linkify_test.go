@@ -0,0 +1,85 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var linkifyTests = []Test{
+	{"no links here", `no links here`},
+	{"see https://example.com/path?a=1&b=2.", `see <a href="https://example.com/path?a=1&amp;b=2" rel="nofollow">https://example.com/path?a=1&amp;b=2</a>.`},
+	{"(http://example.com/wiki/Go_(language))", `(<a href="http://example.com/wiki/Go_(language)" rel="nofollow">http://example.com/wiki/Go_(language)</a>)`},
+	{"HTTP://EXAMPLE.COM", `<a href="http://example.com" rel="nofollow">HTTP://EXAMPLE.COM</a>`},
+	{"mail me@example.com, thanks", `mail <a href="mailto:me@example.com" rel="nofollow">me@example.com</a>, thanks`},
+	{"javascript:alert(1) ftp://example.com", `javascript:alert(1) ftp://example.com`},
+	{"spoof https://аpple.com", `spoof https://аpple.com`},
+	{"<b>not html</b> http://a.com", `&lt;b&gt;not html&lt;/b&gt; <a href="http://a.com" rel="nofollow">http://a.com</a>`},
+	{`http://a.com/"onmouseover="alert(1)`, `<a href="http://a.com/" rel="nofollow">http://a.com/</a>&#34;onmouseover=&#34;alert(1)`},
+}
+
+func TestLinkify(t *testing.T) {
+	for _, test := range linkifyTests {
+		output := Linkify(test.input, LinkifyOptions{})
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	output := Linkify("https://example.com", LinkifyOptions{Target: "_blank"})
+	expected := `<a href="https://example.com" rel="nofollow" target="_blank">https://example.com</a>`
+	if output != expected {
+		t.Fatalf(Format, "https://example.com", expected, output)
+	}
+}
+
+func TestPolicyLinkify(t *testing.T) {
+	p := &Policy{Tags: []string{"p", "a"}, Attributes: []string{"href"}, Linkify: &LinkifyOptions{}}
+	tests := []Test{
+		{`<p>visit https://example.com today</p>`, `<p>visit <a href="https://example.com" rel="nofollow">https://example.com</a> today</p>`},
+		{`<a href="https://example.com">https://example.com</a>`, `<a href="https://example.com">https://example.com</a>`},
+		{`<p>a &lt;b&gt; &amp; c</p>`, `<p>a &lt;b&gt; &amp; c</p>`},
+	}
+	for _, test := range tests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	// Links are not added by a policy which does not allow them
+	input := `<p>visit https://example.com today</p>`
+	for _, p := range []*Policy{
+		{Tags: []string{"p"}, Attributes: []string{"href"}, Linkify: &LinkifyOptions{}},
+		{Tags: []string{"p", "a"}, Attributes: []string{"title"}, Linkify: &LinkifyOptions{}},
+	} {
+		output, err := p.Sanitize(input)
+		if err != nil || output != input {
+			t.Fatalf(Format, input, input, output)
+		}
+	}
+}
+
+func TestLinkifyRules(t *testing.T) {
+	opts := LinkifyOptions{Rules: []LinkRule{MentionRule("/users/$1"), HashtagRule("/tags/$1")}}
+	tests := []Test{
+		{"hello @alice", `hello <a href="/users/alice" rel="nofollow">@alice</a>`},
+		{"#golang and #日本", `<a href="/tags/golang" rel="nofollow">#golang</a> and <a href="/tags/%E6%97%A5%E6%9C%AC" rel="nofollow">#日本</a>`},
+		{"issue #123 is not a tag", `issue #123 is not a tag`},
+		{"mail bob@example.com", `mail <a href="mailto:bob@example.com" rel="nofollow">bob@example.com</a>`},
+		{"a#b and x@y", `a#b and x@y`},
+		{"@bob <b>", `<a href="/users/bob" rel="nofollow">@bob</a> &lt;b&gt;`},
+	}
+	for _, test := range tests {
+		output := Linkify(test.input, opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	// Rules apply to text while sanitizing html
+	p := &Policy{Tags: []string{"p", "a"}, Attributes: []string{"href"}, URLs: URLOptions{AllowRelative: true}, Linkify: &opts}
+	input := `<p>thanks @alice</p><a href="/x">@bob</a>`
+	expected := `<p>thanks <a href="/users/alice" rel="nofollow">@alice</a></p><a href="/x">@bob</a>`
+	if output, _ := p.Sanitize(input); output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+}
@@ -0,0 +1,423 @@
+package sanitize
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	parser "golang.org/x/net/html"
+)
+
+// Characters which may start markdown formatting anywhere in a line
+const markdownPunctuation = "\\`*_[]<>~|!&"
+
+// Markers which start a heading, list, rule or setext heading at the start of a line, after any indentation
+var markdownLineStart = regexp.MustCompile(`(?m)^([ \t]*)([#+=-]|\d+[.)])`)
+
+// Markdown escapes markdown syntax in untrusted text, so that it appears literally when interpolated
+// into a markdown document. Characters used for emphasis, code, links, images, html and tables
+// are escaped with a backslash wherever they appear, and heading, list and rule markers
+// are escaped at the start of a line.
+func Markdown(s string) string {
+	b := strings.Builder{}
+	for _, r := range s {
+		if strings.ContainsRune(markdownPunctuation, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	// Escape the last character of line start markers, so that 1. becomes 1\.
+	return markdownLineStart.ReplaceAllStringFunc(b.String(), func(m string) string {
+		return m[:len(m)-1] + `\` + m[len(m)-1:]
+	})
+}
+
+var (
+	// Fenced code blocks start with ``` or ~~~
+	markdownFence = regexp.MustCompile("^ {0,3}(```|~~~)")
+
+	// Code spans are left unchanged when removing html
+	markdownCodeSpan = regexp.MustCompile("`+[^`]*`+")
+
+	// Html tags, comments and processing instructions, but not autolinks like <https://example.com>
+	markdownHTML = regexp.MustCompile(`<!--[\s\S]*?-->|<[?!][^>]*>|</?[A-Za-z][A-Za-z0-9-]*(\s[^>]*)?/?>`)
+)
+
+// MarkdownStripHTML removes raw html from user markdown before it is rendered, leaving markdown
+// formatting, autolinks such as <https://example.com>, and the contents of code blocks and code spans unchanged.
+// Text inside html elements is kept, but html comments are removed entirely.
+// The rendered html should still be sanitized, as markdown links may contain unsafe urls.
+func MarkdownStripHTML(s string) string {
+	var output, text []string
+	fence := ""
+	code := false
+	list := false
+	blank := true
+	for _, line := range strings.Split(s, "\n") {
+		// Leave fenced and indented code blocks alone, html in other lines is removed together
+		// as tags and comments may span several lines
+		if markdownCodeLine(line, &fence, &code, &list, blank) {
+			if len(text) > 0 {
+				output = append(output, stripMarkdownHTML(strings.Join(text, "\n")))
+				text = nil
+			}
+			output = append(output, line)
+		} else {
+			text = append(text, line)
+		}
+		blank = strings.TrimSpace(line) == ""
+	}
+	if len(text) > 0 {
+		output = append(output, stripMarkdownHTML(strings.Join(text, "\n")))
+	}
+	return strings.Join(output, "\n")
+}
+
+// List items, whose indented content is not a code block
+var markdownListItem = regexp.MustCompile(`^ {0,3}([-+*]|\d+[.)])(\s|$)`)
+
+// markdownCodeLine reports whether line is part of a fenced or indented code block, updating the state
+// of the fence, indented code block and list open before it. An indented line is only code after a blank line,
+// or another line of code, outside a list, otherwise it continues a paragraph or list item.
+func markdownCodeLine(line string, fence *string, code, list *bool, afterBlank bool) bool {
+	if m := markdownFence.FindStringSubmatch(line); m != nil {
+		*code = false
+		if *fence == "" {
+			*fence = m[1]
+		} else if m[1] == *fence {
+			*fence = ""
+		}
+		return true
+	}
+	if *fence != "" {
+		return true
+	}
+
+	indented := strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+	if indented && !*list && (*code || afterBlank) {
+		*code = true
+		return true
+	}
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	*code = false
+	if markdownListItem.MatchString(line) {
+		*list = true
+	} else if !indented && afterBlank {
+		*list = false
+	}
+	return false
+}
+
+// stripMarkdownHTML removes html from lines of markdown, except inside code spans.
+func stripMarkdownHTML(line string) string {
+	b := strings.Builder{}
+	start := 0
+	for _, span := range markdownCodeSpan.FindAllStringIndex(line, -1) {
+		b.WriteString(markdownHTML.ReplaceAllString(line[start:span[0]], ""))
+		b.WriteString(line[span[0]:span[1]])
+		start = span[1]
+	}
+	b.WriteString(markdownHTML.ReplaceAllString(line[start:], ""))
+	return b.String()
+}
+
+// MarkdownPolicy returns a policy allowing the html produced by common markdown renderers,
+// including tables, strikethrough and task list checkboxes, used by RenderMarkdown if no policy is given.
+// Responsive images using picture, source and srcset are allowed, for example to show images for dark mode.
+func MarkdownPolicy() *Policy {
+	return &Policy{
+		Tags: []string{
+			"h1", "h2", "h3", "h4", "h5", "h6", "p", "br", "hr", "em", "strong", "del", "s", "a", "img",
+			"ul", "ol", "li", "blockquote", "pre", "code", "table", "thead", "tbody", "tr", "th", "td", "sup", "sub",
+			"picture", "source",
+		},
+		Attributes:    []string{"href", "src", "srcset", "sizes", "media", "alt", "title", "id", "align", "start", "lang", "dir"},
+		URLAttributes: []string{"src"},
+		URLs:          hrefOptions,
+	}
+}
+
+// ErrMarkdownRenderer is returned by RenderMarkdown if no renderer is given.
+var ErrMarkdownRenderer = errors.New("sanitize: no markdown renderer given")
+
+// RenderMarkdown renders user markdown to html with the renderer given, for example a markdown library,
+// then sanitizes the html with policy, or MarkdownPolicy if policy is nil.
+// Raw html in src is removed by the policy, not before rendering, so html allowed by the policy is kept.
+// If renderer is nil ErrMarkdownRenderer is returned.
+func RenderMarkdown(src []byte, renderer func([]byte) []byte, policy *Policy) (template.HTML, error) {
+	if renderer == nil {
+		return "", ErrMarkdownRenderer
+	}
+	if policy == nil {
+		policy = MarkdownPolicy()
+	}
+	output, err := policy.Sanitize(string(renderer(src)))
+	if err != nil {
+		return "", err
+	}
+	return template.HTML(output), nil
+}
+
+// Emphasis markers written by HTMLToMarkdown.
+var markdownEmphasis = map[string]string{
+	"b": "**", "strong": "**", "i": "*", "em": "*", "del": "~~", "s": "~~", "strike": "~~",
+}
+
+// HTMLToMarkdown converts html to markdown, for example to archive sanitized html as markdown.
+// Headings, paragraphs, emphasis, links, images, lists, blockquotes, code and rules are converted,
+// other elements are removed keeping their text, and text is escaped with Markdown. Links and images
+// with urls which HTMLAllowing would remove are written as text, and scripts and styles are removed.
+func HTMLToMarkdown(s string) (string, error) {
+	tokenizer := parser.NewTokenizer(strings.NewReader(s))
+	w := &markdownWriter{}
+
+	type list struct {
+		ordered bool
+		n       int
+	}
+	var lists []list
+	var links []string
+	// Elements removed with their content, innermost last
+	var ignore []string
+	code := ""     // The tag of the code element or pre block being collected, if any
+	language := "" // The language of a code block
+	raw := strings.Builder{}
+
+	for {
+		tokenType := tokenizer.Next()
+		token := tokenizer.Token()
+		if tokenType == parser.ErrorToken {
+			if err := tokenizer.Err(); err != io.EOF {
+				return "", err
+			}
+			return w.b.String(), nil
+		}
+
+		// The tokenizer reads the content of raw text elements as text even if the tag is self closing
+		if tokenType == parser.SelfClosingTagToken && includes(rawTextTags, token.Data) {
+			tokenType = parser.StartTagToken
+		}
+
+		// Collect the contents of elements which are ignored or written as code
+		if len(ignore) > 0 {
+			if tokenType == parser.StartTagToken || tokenType == parser.SelfClosingTagToken {
+				ignore = ignoreElement(ignore, token.Data)
+			} else if tokenType == parser.EndTagToken {
+				ignore = closeIgnored(ignore, token.Data)
+			}
+			continue
+		}
+		if code != "" {
+			if tokenType == parser.TextToken {
+				raw.WriteString(token.Data)
+			} else if tokenType == parser.StartTagToken && token.Data == "code" {
+				language = strings.TrimPrefix(attribute(token.Attr, "class"), "language-")
+			} else if tokenType == parser.EndTagToken && token.Data == code {
+				if code == "pre" {
+					w.block(2)
+					w.write(markdownCodeBlock(raw.String(), language))
+					w.block(2)
+				} else {
+					w.write(markdownCode(raw.String()))
+				}
+				code = ""
+			}
+			continue
+		}
+
+		switch tokenType {
+		case parser.TextToken:
+			w.text(token.Data)
+
+		case parser.StartTagToken, parser.SelfClosingTagToken:
+			switch tag := token.Data; {
+			case includes(ignoreTags, tag):
+				if tokenType == parser.StartTagToken {
+					ignore = ignoreElement(ignore, tag)
+				}
+			case tag == "pre" || tag == "code":
+				code, language = tag, ""
+				raw.Reset()
+			case len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6':
+				w.block(2)
+				w.write(strings.Repeat("#", int(tag[1]-'0')) + " ")
+			case markdownEmphasis[tag] != "":
+				w.write(markdownEmphasis[tag])
+			case tag == "a":
+				href := attribute(extractPolicy.cleanAttributes(token.Data, token.Attr, nil), "href")
+				links = append(links, href)
+				if href != "" {
+					w.write("[")
+				}
+			case tag == "img":
+				if src := attribute(extractPolicy.cleanAttributes(token.Data, token.Attr, nil), "src"); src != "" {
+					w.write("![" + Markdown(CollapseWhitespace(attribute(token.Attr, "alt"))) + "](" + markdownURL(src) + ")")
+				}
+			case tag == "br":
+				w.write("\\")
+				w.block(1)
+			case tag == "hr":
+				w.block(2)
+				w.write("---")
+				w.block(2)
+			case tag == "ul" || tag == "ol":
+				if len(lists) > 0 {
+					w.block(1)
+				} else {
+					w.block(2)
+				}
+				start, err := strconv.Atoi(attribute(token.Attr, "start"))
+				if err != nil {
+					start = 1
+				}
+				lists = append(lists, list{ordered: tag == "ol", n: start})
+			case tag == "li":
+				marker := "- "
+				if len(lists) > 0 && lists[len(lists)-1].ordered {
+					marker = strconv.Itoa(lists[len(lists)-1].n) + ". "
+					lists[len(lists)-1].n++
+				}
+				w.block(1)
+				w.write(marker)
+				w.marker = true
+				w.prefixes = append(w.prefixes, strings.Repeat(" ", len(marker)))
+			case tag == "blockquote":
+				w.block(2)
+				w.prefixes = append(w.prefixes, "> ")
+			case tag == "td" || tag == "th":
+				w.space = true
+			case tag == "tr":
+				w.block(1)
+			case includes(blockTags, tag):
+				w.block(2)
+			}
+
+		case parser.EndTagToken:
+			switch tag := token.Data; {
+			case len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6':
+				w.block(2)
+			case markdownEmphasis[tag] != "":
+				w.close(markdownEmphasis[tag])
+			case tag == "a" && len(links) > 0:
+				if href := links[len(links)-1]; href != "" {
+					w.close("](" + markdownURL(href) + ")")
+				}
+				links = links[:len(links)-1]
+			case (tag == "ul" || tag == "ol") && len(lists) > 0:
+				lists = lists[:len(lists)-1]
+				w.block(2)
+			case tag == "li" && len(w.prefixes) > 0:
+				w.prefixes = w.prefixes[:len(w.prefixes)-1]
+				w.block(1)
+			case tag == "blockquote" && len(w.prefixes) > 0:
+				w.prefixes = w.prefixes[:len(w.prefixes)-1]
+				w.block(2)
+			case tag == "tr":
+				w.block(1)
+			case includes(blockTags, tag):
+				w.block(2)
+			}
+		}
+	}
+}
+
+// markdownURL escapes the characters in a url which would end a markdown link destination.
+func markdownURL(u string) string {
+	return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(u)
+}
+
+// markdownCode returns s as a markdown code span, using more backticks than any run of backticks in s.
+func markdownCode(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+// markdownCodeBlock returns s as a fenced markdown code block, using a longer fence than any in s.
+func markdownCodeBlock(s string, language string) string {
+	fence := "```"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if strings.ContainsAny(language, "` \t") {
+		language = ""
+	}
+	return fence + language + "\n" + strings.Trim(s, "\n") + "\n" + fence
+}
+
+// markdownWriter writes markdown for HTMLToMarkdown, prefixing lines inside blockquotes and list items.
+type markdownWriter struct {
+	b        strings.Builder
+	prefixes []string
+	breaks   int  // The line breaks to write before the next text
+	space    bool // Whether to write a space before the next text
+	marker   bool // Whether a list marker was just written, so that no break is needed
+}
+
+// block ends the current line, leaving a blank line if n is 2.
+func (w *markdownWriter) block(n int) {
+	if !w.marker && n > w.breaks {
+		w.breaks = n
+	}
+	w.space = false
+}
+
+// text writes text with whitespace collapsed and markdown syntax escaped.
+func (w *markdownWriter) text(s string) {
+	if s == "" {
+		return
+	}
+	if unicode.IsSpace(rune(s[0])) {
+		w.space = true
+	}
+	text := CollapseWhitespace(s)
+	if text != "" {
+		w.write(Markdown(text))
+	}
+	if unicode.IsSpace(rune(s[len(s)-1])) {
+		w.space = true
+	}
+}
+
+// close writes markdown which closes inline formatting, before any space pending.
+func (w *markdownWriter) close(s string) {
+	space := w.space
+	w.space = false
+	w.write(s)
+	w.space = space
+}
+
+// write writes markdown after any pending line breaks or space.
+func (w *markdownWriter) write(s string) {
+	prefix := strings.Join(w.prefixes, "")
+	if w.b.Len() == 0 {
+		w.b.WriteString(prefix)
+	} else if w.breaks > 0 {
+		for i := 1; i < w.breaks; i++ {
+			w.b.WriteString("\n" + strings.TrimRight(prefix, " "))
+		}
+		w.b.WriteString("\n" + prefix)
+	} else if w.space && !strings.HasSuffix(w.b.String(), " ") {
+		w.b.WriteString(" ")
+	}
+	w.b.WriteString(strings.Replace(s, "\n", "\n"+prefix, -1))
+	w.breaks = 0
+	w.space = false
+	w.marker = false
+}
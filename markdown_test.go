@@ -0,0 +1,108 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var markdownTests = []Test{
+	{"plain text", `plain text`},
+	{"*bold* and _em_", `\*bold\* and \_em\_`},
+	{"[click](javascript:alert(1))", `\[click\](javascript:alert(1))`},
+	{"![img](x.png)", `\!\[img\](x.png)`},
+	{"`code` and <b>html</b>", "\\`code\\` and \\<b\\>html\\</b\\>"},
+	{"# heading\n- item\n+ item\n1. first\n  2) second", "\\# heading\n\\- item\n\\+ item\n1\\. first\n  2\\) second"},
+	{"not # a heading, 3.5 - 1", `not # a heading, 3.5 - 1`},
+	{"a | b ~~c~~ &amp;", `a \| b \~\~c\~\~ \&amp;`},
+	{`back\slash`, `back\\slash`},
+}
+
+func TestMarkdown(t *testing.T) {
+	for _, test := range markdownTests {
+		output := Markdown(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var markdownStripHTMLTests = []Test{
+	{"**bold** text", `**bold** text`},
+	{"<script>alert(1)</script> text", `alert(1) text`},
+	{`<img src=x onerror="alert(1)"> and <a href="javascript:x">link</a>`, ` and link`},
+	{"see <https://example.com> or <me@example.com>", `see <https://example.com> or <me@example.com>`},
+	{"a <!-- hidden --> comment", `a  comment`},
+	{"use `<b>` for bold", "use `<b>` for bold"},
+	{"```html\n<b>code</b>\n```\n<b>text</b>", "```html\n<b>code</b>\n```\ntext"},
+	{"    <b>indented code</b>", `    <b>indented code</b>`},
+	{"1 < 2 > 0", `1 < 2 > 0`},
+	{"hi <img\nsrc=x onerror=alert(1)>", `hi `},
+	{"para\n    <img src=x onerror=alert(1)>", "para\n    "},
+	{"- item\n\n    <script>alert(1)</script>", "- item\n\n    alert(1)"},
+	{"a <!--\nhidden\n-->comment", `a comment`},
+	{"text\n\n    <b>code</b>\n\n    <i>more code</i>\n<b>text</b>", "text\n\n    <b>code</b>\n\n    <i>more code</i>\ntext"},
+}
+
+func TestMarkdownStripHTML(t *testing.T) {
+	for _, test := range markdownStripHTMLTests {
+		output := MarkdownStripHTML(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	// A stand in for a markdown library, which passes raw html through
+	renderer := func(src []byte) []byte {
+		return []byte("<p>" + strings.Replace(string(src), "**hello**", "<strong>hello</strong>", -1) + "</p>")
+	}
+
+	tests := []Test{
+		{"**hello** world", `<p><strong>hello</strong> world</p>`},
+		{"<script>alert(1)</script>**hello**", `<p><strong>hello</strong></p>`},
+		{`<a href="javascript:alert(1)" onclick="x">link</a>`, `<p><a>link</a></p>`},
+	}
+	for _, test := range tests {
+		output, err := RenderMarkdown([]byte(test.input), renderer, nil)
+		if err != nil || string(output) != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	// A policy may be given to restrict the output further
+	output, _ := RenderMarkdown([]byte("**hello**"), renderer, &Policy{Tags: []string{"p"}})
+	if output != "<p>hello</p>" {
+		t.Fatalf(Format, "**hello**", "<p>hello</p>", output)
+	}
+
+	if output, err := RenderMarkdown([]byte("**hello**"), nil, nil); err != ErrMarkdownRenderer || output != "" {
+		t.Fatalf("RenderMarkdown with no renderer: %q %v", output, err)
+	}
+}
+
+var htmlToMarkdownTests = []Test{
+	{`<h1>Title</h1><p>Some <b>bold</b> and <em>em</em> text.</p>`, "# Title\n\nSome **bold** and *em* text."},
+	{`<h3> Spaced  heading </h3>`, "### Spaced heading"},
+	{`<p>A <a href="https://example.com/a (b)">link</a> and <a href="javascript:alert(1)">bad</a>.</p>`, "A [link](https://example.com/a%20%28b%29) and bad."},
+	{`<img src="/cat.png" alt="A [cat]">`, `![A \[cat\]](/cat.png)`},
+	{`<ul><li>one</li><li>two <i>2</i></li></ul>`, "- one\n- two *2*"},
+	{`<ol start="3"><li><p>three</p></li><li>four<ul><li>nested</li></ul></li></ol><p>after</p>`, "3. three\n\n4. four\n   - nested\n\nafter"},
+	{`<blockquote><p>quoted</p><p>twice</p></blockquote>`, "> quoted\n>\n> twice"},
+	{`<p>line<br>break</p>`, "line\\\nbreak"},
+	{"<p>Use <code>a`b</code> here</p>", "Use ``a`b`` here"},
+	{"<pre><code class=\"language-go\">func main() {\n\tfmt.Println(\"*\")\n}\n</code></pre>", "```go\nfunc main() {\n\tfmt.Println(\"*\")\n}\n```"},
+	{`<p>1. not a list * or _emphasis_</p><hr><script>alert(1)</script>`, "1\\. not a list \\* or \\_emphasis\\_\n\n---"},
+	{`<div>Tom &amp; Jerry &lt;3</div>`, `Tom \& Jerry \<3`},
+	{`<object><object></object>hidden secret</object><p>shown</p>`, `shown`},
+	{`<p>a</p><script/>alert(1)</script><p>b</p>`, "a\n\nb"},
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	for _, test := range htmlToMarkdownTests {
+		output, err := HTMLToMarkdown(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+
+	parser "golang.org/x/net/html"
+)
+
+// The maximum lengths of the title and description returned by Meta, as shown by most link previews.
+const (
+	maxMetaTitle       = 70
+	maxMetaDescription = 160
+)
+
+// Elements which separate words in the text used by Meta.
+var blockTags = []string{
+	"address", "article", "aside", "blockquote", "br", "dd", "div", "dl", "dt", "figcaption", "figure", "footer",
+	"h1", "h2", "h3", "h4", "h5", "h6", "header", "hr", "li", "ol", "p", "pre", "section", "table", "td", "th", "tr", "ul",
+}
+
+// Meta returns a title, description and image for html content, suitable for og:title, og:description
+// and og:image tags. The title is the text of the first heading, and the description the rest of the text
+// with whitespace collapsed, each truncated at a word boundary with an ellipsis. The image is the url of the
+// first image allowed by HTMLAllowing. Values are plain text with entities decoded, and must be escaped when used.
+// Any of them may be an empty string.
+func Meta(html string) (title, description, firstImage string) {
+	headings := TOC(html)
+	if len(headings) > 0 {
+		title = truncateAtWord(headings[0].Text, maxMetaTitle, "…")
+	}
+
+	// Collect the text outside the first heading, which is used as the title
+	text := strings.Builder{}
+	heading := ""
+	extractTokens(html, func(t parser.Token) {
+		switch t.Type {
+		case parser.StartTagToken, parser.EndTagToken, parser.SelfClosingTagToken:
+			if t.Type == parser.StartTagToken && heading == "" && includes(headingTags, t.Data) {
+				heading = t.Data
+			} else if t.Type == parser.EndTagToken && heading == t.Data {
+				heading = "/" + t.Data
+			}
+			if includes(blockTags, t.Data) {
+				text.WriteString(" ")
+			}
+		case parser.TextToken:
+			if heading == "" || heading[0] == '/' {
+				text.WriteString(t.Data)
+			}
+		}
+	})
+	description = truncateAtWord(CollapseWhitespace(ControlChars(text.String(), ' ', '\n', '\t')), maxMetaDescription, "…")
+
+	if images := ExtractImages(html); len(images) > 0 {
+		firstImage = images[0].URL
+	}
+	return title, description, firstImage
+}
+
+// truncateAtWord shortens s to at most n grapheme clusters including the ellipsis, cutting at the last
+// whitespace before the limit unless s has no whitespace.
+func truncateAtWord(s string, n int, ellipsis string) string {
+	if Length(s) <= n {
+		return s
+	}
+	t := Truncate(s, n-Length(ellipsis), "")
+	if i := strings.LastIndexFunc(t, unicode.IsSpace); i > 0 {
+		t = t[:i]
+	}
+	return strings.TrimRightFunc(t, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsPunct(r) }) + ellipsis
+}
@@ -0,0 +1,38 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var metaTests = []struct {
+	input       string
+	title       string
+	description string
+	image       string
+}{
+	{`<h1>Hello &amp; welcome</h1><p>First paragraph.</p><p>Second<br>line with <b>bold</b> text.</p>`,
+		`Hello & welcome`, `First paragraph. Second line with bold text.`, ``},
+	{`<p>No heading here <img src="javascript:alert(1)"><img src="https://example.com/a.png" alt="A"></p><script>alert("x")</script><h2>Later</h2><p>End</p>`,
+		`Later`, `No heading here End`, `https://example.com/a.png`},
+	{`<h1>` + strings.Repeat("word ", 20) + `</h1>`,
+		strings.TrimSpace(strings.Repeat("word ", 13)) + "…", ``, ``},
+	{`<p>` + strings.Repeat("lorem ipsum, ", 20) + `</p>`,
+		``, strings.TrimSuffix(strings.Repeat("lorem ipsum, ", 12), ", ") + "…", ``},
+	{``, ``, ``, ``},
+}
+
+func TestMeta(t *testing.T) {
+	for _, test := range metaTests {
+		title, description, image := Meta(test.input)
+		if title != test.title {
+			t.Fatalf(Format, test.input, test.title, title)
+		}
+		if description != test.description {
+			t.Fatalf(Format, test.input, test.description, description)
+		}
+		if image != test.image {
+			t.Fatalf(Format, test.input, test.image, image)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package sanitize
+
+import (
+	"time"
+)
+
+// Metrics receives measurements from a Policy, for example to count inputs sanitized, tags removed
+// and urls rejected with Prometheus or OpenTelemetry. Methods are called by the goroutine sanitizing,
+// so a Metrics used by a policy shared between goroutines must be safe for concurrent use.
+type Metrics interface {
+	// Sanitized is called once for each input sanitized, with the time taken and the error returned if any.
+	Sanitized(d time.Duration, err error)
+
+	// Removed is called for each element or attribute removed, after Sanitized.
+	// Elements removed have an empty Attribute, attributes removed because their url was rejected have URL set.
+	Removed(r Removal)
+}
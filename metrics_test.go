@@ -0,0 +1,55 @@
+package sanitize
+
+import (
+	"testing"
+	"time"
+)
+
+// countMetrics counts the measurements it receives.
+type countMetrics struct {
+	inputs, errors, tags, attributes, urls int
+	duration                               time.Duration
+}
+
+func (m *countMetrics) Sanitized(d time.Duration, err error) {
+	m.inputs++
+	m.duration += d
+	if err != nil {
+		m.errors++
+	}
+}
+
+func (m *countMetrics) Removed(r Removal) {
+	switch {
+	case r.URL:
+		m.urls++
+	case r.Attribute != "":
+		m.attributes++
+	default:
+		m.tags++
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	m := &countMetrics{}
+	inputs := []string{
+		`<p onclick="a()">text<script>alert(1)</script></p>`,
+		`<a href="javascript:alert(1)">link</a><img src="data:image/png;base64,AA" title="t">`,
+		`<p>clean</p>`,
+	}
+	for _, input := range inputs {
+		if _, err := Sanitize(input, WithMetrics(m)); err != nil {
+			t.Fatalf("Sanitize error for %s: %s", input, err)
+		}
+	}
+	if m.inputs != 3 || m.errors != 0 || m.tags != 1 || m.attributes != 1 || m.urls != 2 || m.duration <= 0 {
+		t.Fatalf("Metrics counted %+v", m)
+	}
+
+	// Errors are counted, and SanitizeReport returns the same removals
+	p := &Policy{Tags: []string{"p"}, MaxTokenLength: 16, Metrics: m}
+	_, report, err := p.SanitizeReport(`<p><i>` + "long text which is not buffered")
+	if err == nil || m.inputs != 4 || m.errors != 1 || m.tags != 2 || len(report.Removed) != 1 {
+		t.Fatalf("Metrics counted %+v with report %v", m, report)
+	}
+}
@@ -0,0 +1,43 @@
+package sanitize
+
+// ChangeSet describes the changes made to stored html by Migrate.
+type ChangeSet struct {
+	// Changed reports whether the html sanitized by the new policy differs from the html sanitized by the old policy.
+	Changed bool
+
+	// Removed lists the elements and attributes removed by the new policy but not by the old, in the order they were found.
+	Removed []Removal
+}
+
+// Migrate sanitizes html stored after sanitizing with the policy from using the policy to, and reports
+// what the new policy changes, so that stored html may be checked or updated in bulk when a policy is tightened.
+// Changes are measured against the html sanitized by from, so differences in how the stored html was
+// written, such as <br/> for <br>, are not reported.
+func Migrate(old string, from, to *Policy) (string, ChangeSet, error) {
+	var changes ChangeSet
+
+	before, fromReport, err := from.SanitizeReport(old)
+	if err != nil {
+		return "", changes, err
+	}
+	after, toReport, err := to.SanitizeReport(old)
+	if err != nil {
+		return "", changes, err
+	}
+
+	// Count the removals made by the old policy, so that only those made by the new policy are reported
+	removed := make(map[Removal]int)
+	for _, r := range fromReport.Removed {
+		removed[r]++
+	}
+	for _, r := range toReport.Removed {
+		if removed[r] > 0 {
+			removed[r]--
+			continue
+		}
+		changes.Removed = append(changes.Removed, r)
+	}
+	changes.Changed = before != after
+
+	return after, changes, nil
+}
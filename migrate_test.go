@@ -0,0 +1,39 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	from := &Policy{Tags: defaultTags, Attributes: defaultAttributes, URLs: hrefOptions}
+	to := &Policy{Tags: []string{"p", "a", "b"}, Attributes: []string{"href"}, URLs: URLOptions{Schemes: []string{"https"}}}
+
+	old := `<p class="intro">Hello <b>world</b><br/><a href="http://example.com/">link</a><img src="https://example.com/a.png"></p>`
+	expected := `<p>Hello <b>world</b><a>link</a></p>`
+	removed := []Removal{
+		{Tag: "p", Attribute: "class", Value: "intro"},
+		{Tag: "br"},
+		{Tag: "a", Attribute: "href", Value: "http://example.com/", URL: true},
+		{Tag: "img"},
+	}
+
+	output, changes, err := Migrate(old, from, to)
+	if err != nil || output != expected {
+		t.Fatalf(Format, old, expected, output)
+	}
+	if !changes.Changed || len(changes.Removed) != len(removed) {
+		t.Fatalf("Migrate(%q) changes %v, want %v", old, changes, removed)
+	}
+	for i, r := range changes.Removed {
+		if r != removed[i] {
+			t.Fatalf("Migrate(%q) changes %v, want %v", old, changes, removed)
+		}
+	}
+
+	// Html written differently by the old policy is not a change, nor are elements both policies remove
+	old = `<p>Hello<br/>world</p><script>alert(1)</script>`
+	output, changes, err = Migrate(old, from, from)
+	if err != nil || output != `<p>Hello<br/>world</p>` || changes.Changed || len(changes.Removed) != 0 {
+		t.Fatalf("Migrate(%q) changes %v output %q", old, changes, output)
+	}
+}
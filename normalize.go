@@ -0,0 +1,34 @@
+package sanitize
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizationForm selects the unicode normalization applied to input before it is sanitized.
+type NormalizationForm int
+
+// Supported normalization forms.
+const (
+	// NoNormalization leaves input unchanged.
+	NoNormalization NormalizationForm = iota
+
+	// NFC composes characters, so that for example e followed by a combining acute accent becomes é.
+	// Text on macOS file systems is usually decomposed, NFC makes it match text typed elsewhere.
+	NFC
+
+	// NFKC composes characters and replaces compatibility characters with their equivalents,
+	// for example the ligature ﬁ becomes fi, full width Ａ becomes A and ² becomes 2.
+	NFKC
+)
+
+// Normalize returns s in the normalization form given, so that visually identical strings
+// have identical representations and may be compared or deduplicated.
+func Normalize(s string, form NormalizationForm) string {
+	switch form {
+	case NFC:
+		return norm.NFC.String(s)
+	case NFKC:
+		return norm.NFKC.String(s)
+	}
+	return s
+}
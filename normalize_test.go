@@ -0,0 +1,94 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input    string
+		form     NormalizationForm
+		expected string
+	}{
+		{"café", NoNormalization, "café"},
+		{"café", NFC, "café"},
+		{"café", NFC, "café"},
+		{"café", NFKC, "café"},
+		{"ﬁle Ａ²", NFC, "ﬁle Ａ²"},
+		{"ﬁle Ａ²", NFKC, "file A2"},
+	}
+	for _, test := range tests {
+		output := Normalize(test.input, test.form)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestNormalizeSlug(t *testing.T) {
+	// Precomposed and decomposed input give the same slug and file name
+	composed, decomposed := "Schöne Grüße.txt", "Schöne Grüße.txt"
+	if Path(composed) != Path(decomposed) {
+		t.Fatalf(Format, decomposed, Path(composed), Path(decomposed))
+	}
+	if Name(decomposed) != `schoene-gruesse.txt` {
+		t.Fatalf(Format, decomposed, `schoene-gruesse.txt`, Name(decomposed))
+	}
+
+	input := "ﬁnal Ｒｅｐｏｒｔ"
+	output := Slug(input, SlugOptions{Normalization: NFKC})
+	if output != `final-report` {
+		t.Fatalf(Format, input, `final-report`, output)
+	}
+}
+
+func TestHTMLText(t *testing.T) {
+	input := "<p>café</p>"
+	if HTML(input) != "café\n" {
+		t.Fatalf(Format, input, "café\n", HTML(input))
+	}
+	output := HTMLText(input, HTMLOptions{Normalization: NFC})
+	if output != "café\n" {
+		t.Fatalf(Format, input, "café\n", output)
+	}
+}
+
+func TestHTMLTextTransform(t *testing.T) {
+	f := &WordFilter{}
+	f.Add("darn")
+	tests := []struct {
+		input    string
+		opts     HTMLOptions
+		expected string
+	}{
+		{"<p>Hello  <b>world</b></p>\n<p>again</p>", HTMLOptions{Text: CollapseWhitespace}, "Hello world again"},
+		{"<p>Darn &amp; blast</p>", HTMLOptions{Text: f.Replace}, "**** & blast\n"},
+		{"<p>a &lt;b&gt;</p>", HTMLOptions{Text: strings.ToUpper}, "A &lt;B&gt;\n"},
+	}
+	for _, test := range tests {
+		output := HTMLText(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestHTMLEscaping(t *testing.T) {
+	input := `<p>Tom &amp; "Jerry's" <b>&lt;cat&gt;</b> &amp;&amp;</p>`
+	tests := []struct {
+		escaping Escaping
+		expected string
+	}{
+		{EscapeDefault, "Tom & \"Jerry's\" &lt;cat&gt; &amp;&amp;\n"},
+		{EscapeNone, "Tom & \"Jerry's\" <cat> &&\n"},
+		{EscapeMinimal, "Tom &amp; \"Jerry's\" &lt;cat&gt; &amp;&amp;\n"},
+		{EscapeFull, "Tom &amp; &#34;Jerry&#39;s&#34; &lt;cat&gt; &amp;&amp;\n"},
+	}
+	for _, test := range tests {
+		output := HTMLText(input, HTMLOptions{Escaping: test.escaping})
+		if output != test.expected {
+			t.Fatalf(Format, input, test.expected, output)
+		}
+	}
+}
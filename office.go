@@ -0,0 +1,32 @@
+package sanitize
+
+import (
+	"regexp"
+)
+
+// Office applications wrap lines within paragraphs in the html they copy
+var officeWhitespace = regexp.MustCompile(`[ \t\r\n]+`)
+
+// OfficePastePolicy returns a policy which cleans html pasted from Microsoft Word or Google Docs
+// into a rich text editor, producing simple semantic html. Namespaced tags such as <o:p>,
+// spans, styles including mso-* properties, classes such as MsoNormal, conditional comments
+// and the list bullets they hide are removed, curly quotes and non-breaking spaces are
+// replaced with ascii, and line breaks within text are collapsed.
+func OfficePastePolicy() *Policy {
+	return &Policy{
+		Tags: []string{
+			"p", "br", "h1", "h2", "h3", "h4", "h5", "h6", "b", "strong", "i", "em", "u", "s", "sub", "sup",
+			"ul", "ol", "li", "a", "blockquote", "table", "thead", "tbody", "tr", "th", "td",
+		},
+		Attributes:  []string{"href", "colspan", "rowspan", "lang", "dir"},
+		URLs:        URLOptions{RejectConfusable: true},
+		OfficePaste: true,
+		Text:        officeText,
+	}
+}
+
+// officeText replaces typography and line breaks in text pasted from office applications.
+func officeText(s string) string {
+	s = Typography(s, TypographyOptions{KeepDashes: true, KeepEllipses: true})
+	return officeWhitespace.ReplaceAllString(s, " ")
+}
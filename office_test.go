@@ -0,0 +1,27 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var officePasteTests = []Test{
+	{`<p class=MsoNormal style='mso-margin-top-alt:auto'>Hello <b style='mso-bidi-font-weight:normal'>world</b><o:p></o:p></p>`, `<p>Hello <b>world</b></p>`},
+	{"<p class=MsoNormal>A paragraph\r\nwrapped by Word<o:p>&nbsp;</o:p></p>", `<p>A paragraph wrapped by Word </p>`},
+	{`<p class=MsoListParagraphCxSpFirst style='text-indent:-18.0pt;mso-list:l0 level1 lfo1'><![if !supportLists]><span style='font-family:Symbol'>·<span style='font:7.0pt "Times New Roman"'>&nbsp;&nbsp; </span></span><![endif]>First item</p>`, `<p>First item</p>`},
+	{`<!--[if gte mso 9]><xml><w:WordDocument><w:View>Normal</w:View></w:WordDocument></xml><![endif]--><p>Text</p>`, `<p>Text</p>`},
+	{`<p><span style="font-weight:700">&ldquo;Quoted&rdquo; &lsquo;text&rsquo;</span></p>`, `<p>&#34;Quoted&#34; &#39;text&#39;</p>`},
+	{`<b id="docs-internal-guid-1234"><a href="https://example.com" style="text-decoration:none">link</a></b>`, `<b><a href="https://example.com">link</a></b>`},
+}
+
+func TestOfficePastePolicy(t *testing.T) {
+	p := OfficePastePolicy()
+	for _, test := range officePasteTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil {
+			t.Fatalf(Format, test.input, test.expected, err)
+		}
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package sanitize
+
+// Option configures functions which accept options, such as Sanitize, Path and Name.
+// Options which do not apply to a function are ignored by it, so the same options
+// may be shared, for example WithMaxLength applies to Path and Name but not to Sanitize.
+// SlugOptions, URLOptions and LinkifyOptions are also options, so the settings used with
+// Slug, URL and Linkify may be passed to these functions:
+//
+//	sanitize.Path(s, sanitize.SlugOptions{Reserved: sanitize.ReservedSlugs}, sanitize.WithMaxLength(40))
+type Option interface {
+	apply(*options)
+}
+
+// optionFunc is an Option set by a With func.
+type optionFunc func(*options)
+
+func (f optionFunc) apply(o *options) {
+	f(o)
+}
+
+// apply sets all slug options used by Path and Name, options given after it adjust them.
+func (opts SlugOptions) apply(o *options) {
+	o.slug = opts
+}
+
+// apply sets the checks applied to urls by Sanitize.
+func (opts URLOptions) apply(o *options) {
+	o.policy.URLs = opts
+}
+
+// apply links urls in text sanitized by Sanitize, see Policy.Linkify.
+func (opts LinkifyOptions) apply(o *options) {
+	o.policy.Linkify = &opts
+}
+
+// options holds the settings made by Option funcs.
+type options struct {
+	policy Policy
+	slug   SlugOptions
+}
+
+// newOptions returns the default policy and slug options after applying opts.
+func newOptions(opts []Option) *options {
+	o := &options{
+		policy: *DefaultPolicy(),
+	}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+	return o
+}
+
+// WithPolicy sanitizes html with a copy of policy, such as FeedPolicy(), instead of the default tags and attributes.
+// Options given after WithPolicy adjust the copy. If policy is nil the default policy is used.
+func WithPolicy(policy *Policy) Option {
+	return optionFunc(func(o *options) {
+		if policy == nil {
+			o.policy = *DefaultPolicy()
+			return
+		}
+		o.policy = *policy
+	})
+}
+
+// WithTags sets the html elements allowed.
+func WithTags(tags ...string) Option {
+	return optionFunc(func(o *options) {
+		o.policy.Tags = tags
+	})
+}
+
+// WithAttributes sets the html attributes allowed.
+func WithAttributes(attributes ...string) Option {
+	return optionFunc(func(o *options) {
+		o.policy.Attributes = attributes
+	})
+}
+
+// WithSchemes sets the url schemes allowed in links, by default http, https and mailto.
+// Schemes may be added as well as removed, but javascript: and data: urls are always removed.
+func WithSchemes(schemes ...string) Option {
+	return optionFunc(func(o *options) {
+		o.policy.URLs.Schemes = schemes
+	})
+}
+
+// WithMaxLength limits the number of characters in paths and names, see SlugOptions.MaxLength.
+func WithMaxLength(n int) Option {
+	return optionFunc(func(o *options) {
+		o.slug.MaxLength = n
+	})
+}
+
+// WithSeparator joins words in paths and names with r, such as ~, instead of -.
+// Runs of removed characters are also replaced with r. If r is not allowed in the output, such as _, - is used,
+// see SlugOptions.Separator and SlugOptions.Replacement.
+func WithSeparator(r rune) Option {
+	return optionFunc(func(o *options) {
+		o.slug.Separator = r
+		o.slug.Replacement = r
+	})
+}
+
+// WithPreserveCase keeps the case of letters in paths and names.
+func WithPreserveCase() Option {
+	return optionFunc(func(o *options) {
+		o.slug.PreserveCase = true
+	})
+}
+
+// WithLang selects the case rules and transliterations for a language in paths and names, see AccentsLang.
+func WithLang(lang string) Option {
+	return optionFunc(func(o *options) {
+		o.slug.Lang = lang
+	})
+}
+
+// WithReserved rejects paths and names containing a reserved segment, such as those in ReservedSlugs.
+func WithReserved(reserved ...string) Option {
+	return optionFunc(func(o *options) {
+		o.slug.Reserved = reserved
+	})
+}
+
+// WithMaxTokenLength limits the bytes buffered for a single tag, comment or run of text, see Policy.MaxTokenLength.
+func WithMaxTokenLength(n int) Option {
+	return optionFunc(func(o *options) {
+		o.policy.MaxTokenLength = n
+	})
+}
+
+// WithPartialOutput returns the html sanitized before an error with the error, instead of an empty string.
+func WithPartialOutput() Option {
+	return optionFunc(func(o *options) {
+		o.policy.PartialOutput = true
+	})
+}
+
+// WithMetrics passes the time taken to sanitize and the elements and attributes removed to m, see Metrics.
+func WithMetrics(m Metrics) Option {
+	return optionFunc(func(o *options) {
+		o.policy.Metrics = m
+	})
+}
+
+// WithCSP removes inline styles and scripts so that output is compatible with a strict Content-Security-Policy,
+// style elements are kept with nonce if it is not empty and style is allowed, see Policy.CSP.
+func WithCSP(nonce string) Option {
+	return optionFunc(func(o *options) {
+		o.policy.CSP = true
+		o.policy.Nonce = nonce
+	})
+}
+
+// WithStructuredData keeps json-ld script elements and microdata attributes, see Policy.StructuredData.
+func WithStructuredData() Option {
+	return optionFunc(func(o *options) {
+		o.policy.StructuredData = true
+	})
+}
+
+// WithConditionalComments keeps Outlook conditional comments and VML fallbacks, see Policy.ConditionalComments.
+func WithConditionalComments() Option {
+	return optionFunc(func(o *options) {
+		o.policy.ConditionalComments = true
+	})
+}
+
+// WithAudit calls audit for each script element, event handler or script url removed, see Policy.Audit.
+func WithAudit(audit func(AuditEvent)) Option {
+	return optionFunc(func(o *options) {
+		o.policy.Audit = audit
+	})
+}
+
+// WithCompat selects the output for html which has been sanitized differently between versions, see CompatLevel.
+func WithCompat(level CompatLevel) Option {
+	return optionFunc(func(o *options) {
+		o.policy.Compat = level
+	})
+}
+
+// Sanitize sanitizes html as HTMLAllowing does, using the default policy unless changed by opts, see SetDefaultPolicy.
+// Unlike HTMLAllowing it accepts options, for example:
+//
+//	sanitize.Sanitize(s, sanitize.WithTags("p", "a"), sanitize.WithSchemes("https"))
+func Sanitize(s string, opts ...Option) (string, error) {
+	o := newOptions(opts)
+	return o.policy.Sanitize(s)
+}
@@ -0,0 +1,62 @@
+package sanitize
+
+import "testing"
+
+func TestSanitizeOptions(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected string
+	}{
+		{`<p>Hello <b>there</b></p><script>x</script>`, nil, `<p>Hello <b>there</b></p>`},
+		{`<p>Hello <b>there</b></p>`, []Option{WithTags("b")}, `Hello <b>there</b>`},
+		{`<p id="a" class="b">x</p>`, []Option{WithAttributes("class")}, `<p class="b">x</p>`},
+		{`<a href="mailto:a@example.com">a</a>`, []Option{WithSchemes("https")}, `<a>a</a>`},
+		{`<a href="https://example.com/">a</a>`, []Option{WithSchemes("https")}, `<a href="https://example.com/">a</a>`},
+		{`<a href="ftp://x.com/f">f</a><a href="tel:+441234">t</a>`, []Option{WithSchemes("ftp", "tel", "https")}, `<a href="ftp://x.com/f">f</a><a href="tel:+441234">t</a>`},
+		{`<a href="/f">f</a><a href="//evil.com/">e</a><a href="javascript:alert(1)">j</a>`, []Option{WithSchemes("ftp", "javascript")}, `<a href="/f">f</a><a>e</a><a>j</a>`},
+		{`<p>x</p><figure>y</figure>`, []Option{WithPolicy(FeedPolicy())}, `<p>x</p>y`},
+		{`<p>x</p><b>y</b>`, []Option{WithPolicy(FeedPolicy()), WithTags("b")}, `x<b>y</b>`},
+		{`<p>Hello <b>there</b></p><script>x</script>`, []Option{WithPolicy(nil)}, `<p>Hello <b>there</b></p>`},
+		{`<a href="mailto:a@example.com">a</a>`, []Option{URLOptions{Schemes: []string{"https"}}}, `<a>a</a>`},
+		{`<p>see https://example.com</p>`, []Option{LinkifyOptions{Target: "_blank"}}, `<p>see <a href="https://example.com" rel="nofollow" target="_blank">https://example.com</a></p>`},
+	}
+
+	for _, test := range tests {
+		output, err := Sanitize(test.input, test.opts...)
+		if err != nil {
+			t.Fatalf("Sanitize error for %s: %s", test.input, err)
+		}
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestPathNameOptions(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  []Option
+		path  string
+		name  string
+	}{
+		{"Hello World.txt", nil, "hello-world.txt", "hello-world.txt"},
+		{"Hello World.txt", []Option{WithSeparator('_')}, "hello-world.txt", "hello-world.txt"},
+		{"Hello World.txt", []Option{WithSeparator('~')}, "hello~world.txt", "hello-world.txt"},
+		{"Hello World.txt", []Option{WithPreserveCase()}, "Hello-World.txt", "Hello-World.txt"},
+		{"a long file name", []Option{WithMaxLength(6)}, "a-long", "a-long"},
+		{"Admin", []Option{SlugOptions{Reserved: ReservedSlugs, ReservedSuffix: "-page"}}, "admin-page", "admin-page"},
+		{"A Long File Name", []Option{SlugOptions{PreserveCase: true}, WithMaxLength(6)}, "A-Long", "A-Long"},
+	}
+
+	for _, test := range tests {
+		output := Path(test.input, test.opts...)
+		if output != test.path {
+			t.Fatalf(Format, test.input, test.path, output)
+		}
+		output = Name(test.input, test.opts...)
+		if output != test.name {
+			t.Fatalf(Format, test.input, test.name, output)
+		}
+	}
+}
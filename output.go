@@ -0,0 +1,260 @@
+package sanitize
+
+import (
+	"bytes"
+	"strings"
+
+	parser "golang.org/x/net/html"
+)
+
+// outputToken is a token kept by a policy, with the html to write in its place if the token has been rewritten.
+type outputToken struct {
+	parser.Token
+	html string
+}
+
+// String returns the html for the token.
+func (t outputToken) String() string {
+	if t.html != "" {
+		return t.html
+	}
+	return t.Token.String()
+}
+
+// render applies the output rules of the policy to the tokens kept, and returns the html.
+func (p *Policy) render(output []outputToken) string {
+	if p.Compat >= Compat2 {
+		output = closeElements(output, p.Compat >= Compat3)
+	}
+	if p.Containment {
+		output = containElements(output)
+	}
+	if p.CollapseFormatting {
+		output = collapseFormatting(output)
+	}
+	if p.RemoveEmpty {
+		output = removeEmpty(output)
+	}
+	if p.HeadingIDs {
+		output = headingIDs(output)
+	}
+
+	buffer := bytes.NewBufferString("")
+	for _, t := range output {
+		buffer.WriteString(t.String())
+	}
+	return buffer.String()
+}
+
+// Elements which are closed by the start of another element, if that element is opened directly inside them.
+var impliedEndTags = map[string][]string{
+	"li":     {"li"},
+	"dt":     {"dt", "dd"},
+	"dd":     {"dt", "dd"},
+	"p":      {"p"},
+	"tr":     {"tr", "td", "th"},
+	"td":     {"td", "th"},
+	"th":     {"td", "th"},
+	"option": {"option"},
+}
+
+// closeElements adds end tags for elements left open, closing elements which end implicitly
+// before the next element of their kind, and elements left open inside an element before its end tag.
+// End tags which do not match an open element are removed if removeStray is true.
+func closeElements(tokens []outputToken, removeStray bool) []outputToken {
+	var output []outputToken
+	var open []string
+
+	// The number of open elements with each name, so that end tags without a match are not searched for
+	count := make(map[string]int)
+	closeTo := func(i int) {
+		for len(open) > i {
+			name := open[len(open)-1]
+			open = open[:len(open)-1]
+			count[name]--
+			output = append(output, outputToken{Token: parser.Token{Type: parser.EndTagToken, Data: name}})
+		}
+	}
+	for _, t := range tokens {
+		switch t.Type {
+		case parser.StartTagToken:
+			if includes(voidTags, t.Data) {
+				break
+			}
+			for len(open) > 0 && includes(impliedEndTags[t.Data], open[len(open)-1]) {
+				closeTo(len(open) - 1)
+			}
+			open = append(open, t.Data)
+			count[t.Data]++
+		case parser.EndTagToken:
+			if count[t.Data] == 0 && removeStray {
+				continue
+			}
+			for i := len(open) - 1; i >= 0 && count[t.Data] > 0; i-- {
+				if open[i] == t.Data {
+					closeTo(i + 1)
+					open = open[:i]
+					count[t.Data]--
+					break
+				}
+			}
+		}
+		output = append(output, t)
+	}
+	closeTo(0)
+	return output
+}
+
+// The parents allowed for list and table elements by Containment.
+var validParents = map[string][]string{
+	"li":       {"ul", "ol", "menu"},
+	"dt":       {"dl"},
+	"dd":       {"dl"},
+	"tr":       {"table", "thead", "tbody", "tfoot"},
+	"td":       {"tr"},
+	"th":       {"tr"},
+	"thead":    {"table"},
+	"tbody":    {"table"},
+	"tfoot":    {"table"},
+	"caption":  {"table"},
+	"colgroup": {"table"},
+	"col":      {"table", "colgroup"},
+}
+
+// Stray elements which are replaced by another element rather than removed by Containment.
+var strayReplacements = map[string]string{
+	"li": "p",
+}
+
+// containElements removes or replaces elements which are not inside one of their valid parents,
+// keeping their content. Elements inside a removed parent are checked against the elements kept.
+func containElements(tokens []outputToken) []outputToken {
+	type element struct {
+		name   string
+		output string
+	}
+
+	var output []outputToken
+	var open []element
+
+	// The number of open elements with each name, so that end tags without a match are not searched for
+	count := make(map[string]int)
+	for _, t := range tokens {
+		switch t.Type {
+		case parser.StartTagToken, parser.SelfClosingTagToken:
+			tag, name := t.Data, t.Data
+			if parents, ok := validParents[tag]; ok {
+				parent := ""
+				for i := len(open) - 1; i >= 0 && parent == ""; i-- {
+					parent = open[i].output
+				}
+				if !includes(parents, parent) {
+					name = strayReplacements[tag]
+					t.Data = name
+					t.Attr = nil
+				}
+			}
+			if t.Type == parser.StartTagToken && !includes(voidTags, tag) {
+				open = append(open, element{name: tag, output: name})
+				count[tag]++
+			}
+			if name == "" {
+				continue
+			}
+		case parser.EndTagToken:
+			// Close the innermost open element with this name, writing the end tag it was given
+			for i := len(open) - 1; i >= 0 && count[t.Data] > 0; i-- {
+				if open[i].name == t.Data {
+					count[t.Data]--
+					t.Data = open[i].output
+					open = append(open[:i], open[i+1:]...)
+					break
+				}
+			}
+			if t.Data == "" {
+				continue
+			}
+		}
+		output = append(output, t)
+	}
+	return output
+}
+
+// Elements which are kept by RemoveEmpty even if they have no content.
+var keepEmptyTags = []string{"td", "th", "textarea"}
+
+// removeEmpty removes elements without attributes which contain only whitespace,
+// including elements which contain only other empty elements.
+func removeEmpty(tokens []outputToken) []outputToken {
+	var output []outputToken
+	var open []int
+	for _, t := range tokens {
+		switch t.Type {
+		case parser.StartTagToken:
+			if !includes(voidTags, t.Data) {
+				open = append(open, len(output))
+			}
+		case parser.EndTagToken:
+			if len(open) > 0 && output[open[len(open)-1]].Data == t.Data {
+				start := open[len(open)-1]
+				open = open[:len(open)-1]
+				if emptyElement(output[start:]) {
+					output = output[:start]
+					continue
+				}
+			}
+		}
+		output = append(output, t)
+	}
+	return output
+}
+
+// emptyElement reports whether the tokens from a start tag onwards contain only whitespace,
+// and the start tag has no attributes.
+func emptyElement(tokens []outputToken) bool {
+	start := tokens[0]
+	if len(start.Attr) > 0 || includes(keepEmptyTags, start.Data) {
+		return false
+	}
+	for _, t := range tokens[1:] {
+		if t.Type != parser.TextToken || t.html != "" || strings.TrimSpace(t.Data) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Formatting elements which have no further effect when nested in the same element.
+var formattingTags = []string{"b", "strong", "i", "em", "u", "s", "strike", "del", "ins", "mark", "code"}
+
+// collapseFormatting removes formatting tags without attributes nested inside an element with the same tag, with their end tags.
+func collapseFormatting(tokens []outputToken) []outputToken {
+	var output []outputToken
+
+	// Whether each open element is redundant, by name, innermost last
+	open := make(map[string][]bool)
+	for _, t := range tokens {
+		switch t.Type {
+		case parser.StartTagToken:
+			if includes(voidTags, t.Data) {
+				break
+			}
+			redundant := len(t.Attr) == 0 && len(open[t.Data]) > 0 && includes(formattingTags, t.Data)
+			open[t.Data] = append(open[t.Data], redundant)
+			if redundant {
+				continue
+			}
+		case parser.EndTagToken:
+			// Close the innermost open element with this name
+			if n := len(open[t.Data]); n > 0 {
+				redundant := open[t.Data][n-1]
+				open[t.Data] = open[t.Data][:n-1]
+				if redundant {
+					continue
+				}
+			}
+		}
+		output = append(output, t)
+	}
+	return output
+}
@@ -0,0 +1,67 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// The shortest and longest phone numbers accepted by Phone, E.164 numbers have at most 15 digits
+const (
+	minPhoneDigits = 3
+	maxPhoneDigits = 15
+)
+
+var (
+	// Extensions are removed from the end of phone numbers, such as ext. 123, x123 or #123
+	phoneExtension = regexp.MustCompile(`(?i)\s*(extension|ext\.?|x|#)\s*\d+\s*$`)
+
+	// Words in capitals in vanity numbers such as 1-800-FLOWERS
+	phoneLetters = regexp.MustCompile(`[A-Za-z]+`)
+)
+
+// The digits for letters on a telephone keypad, from A to Z
+const phoneKeypad = "22233344455566677778889999"
+
+// Phone returns a phone number with only its digits, and a leading + if it starts with one,
+// removing spaces, punctuation such as ( ) - . and any extension, and the trunk prefix (0) from
+// international numbers such as +44 (0)20 7946 0958. Full width digits are converted to ascii,
+// and words in capitals after the first digit are converted to keypad digits, so 1-800-FLOWERS gives 18003569377.
+// If the number has fewer than 3 or more than 15 digits an empty string is returned.
+func Phone(s string) string {
+	s = strings.TrimSpace(norm.NFKC.String(s))
+	s = phoneExtension.ReplaceAllString(s, "")
+
+	// Letters in vanity numbers are dialled using the keypad, other words are removed
+	if start := strings.IndexAny(s, "0123456789"); start >= 0 {
+		s = s[:start] + phoneLetters.ReplaceAllStringFunc(s[start:], phoneWord)
+	}
+
+	// The trunk prefix written as (0) in international numbers is not dialled
+	if strings.HasPrefix(s, "+") {
+		s = strings.Replace(s, "(0)", "", 1)
+	}
+
+	phone := Digits(s)
+	if len(phone) < minPhoneDigits || len(phone) > maxPhoneDigits {
+		return ""
+	}
+	if strings.HasPrefix(s, "+") {
+		phone = "+" + phone
+	}
+
+	return phone
+}
+
+// phoneWord returns the keypad digits for a word in capitals, or an empty string for other words.
+func phoneWord(word string) string {
+	if strings.ToUpper(word) != word {
+		return ""
+	}
+	b := strings.Builder{}
+	for _, r := range word {
+		b.WriteByte(phoneKeypad[r-'A'])
+	}
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var phoneTests = []Test{
+	{"+44 (0)20 7946 0958", `+442079460958`},
+	{"(555) 123-4567", `5551234567`},
+	{"  +1.555.123.4567  ", `+15551234567`},
+	{"555-123-4567 ext. 89", `5551234567`},
+	{"555 123 4567 x89", `5551234567`},
+	{"555 123 4567 extension 89", `5551234567`},
+	{"020 7946 0958 #12", `02079460958`},
+	{"0800 EXTRA", `080039872`},
+	{"1-800-FLOWERS", `18003569377`},
+	{"555-1234 (home)", `5551234`},
+	{"Fax: 555 1234", `5551234`},
+	{"+1 555++123", `+1555123`},
+	{"call 911", `911`},
+	{"１２３４５", `12345`},
+	{"12", ``},
+	{"1234567890123456", ``},
+	{"<script>alert(1)</script>", ``},
+}
+
+func TestPhone(t *testing.T) {
+	for _, test := range phoneTests {
+		output := Phone(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,819 @@
+package sanitize
+
+import (
+	"errors"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	parser "golang.org/x/net/html"
+	"golang.org/x/text/language"
+)
+
+// ErrSanitizeFailed is returned instead of panicking if sanitizing html fails unexpectedly,
+// for example because a function set on the policy panicked.
+var ErrSanitizeFailed = errors.New("sanitize: sanitizing html failed unexpectedly")
+
+// The bytes buffered for a single token if Policy.MaxTokenLength is zero.
+const defaultMaxTokenLength = 16 << 20
+
+// Policy sets the tags and attributes allowed when sanitizing html, and how urls in attributes are checked.
+// HTMLAllowing uses a policy built from its arguments, presets such as FeedPolicy return a policy
+// which may be adjusted before use. A policy should not be modified while in use.
+type Policy struct {
+	// Tags lists the elements allowed, other elements are removed but their text content is kept.
+	Tags []string
+
+	// Attributes lists the attributes allowed on allowed elements.
+	Attributes []string
+
+	// AttributePatterns restricts the values of attributes, attributes with values which do not match
+	// the pattern given for their name are removed. For example a pattern of ^language-[\w+-]+$ for class
+	// allows only classes used to mark the language of code blocks.
+	AttributePatterns map[string]*regexp.Regexp
+
+	// URLAttributes lists attributes such as src which hold urls to be checked and normalised using URLs.
+	// Attributes which may hold scripts such as href, action, formaction, cite, poster, background,
+	// longdesc and xlink:href are always checked, as are each of the urls in srcset and ping.
+	URLAttributes []string
+
+	// StyleProperties lists the css properties allowed in style attributes if style is allowed, see EmailPolicy.
+	// Other declarations are removed, as are values containing urls, escapes or functions other than colours.
+	// If empty, style attributes are only checked for script urls, which does not prevent styles such as
+	// position:fixed overlays or url() requests to other sites.
+	StyleProperties []string
+
+	// URLs sets the schemes allowed in url attributes, and whether relative urls are allowed.
+	URLs URLOptions
+
+	// Text transforms text content before it is escaped, for example to censor words with a WordFilter.
+	Text func(string) string
+
+	// Linkify converts urls and email addresses in text to links as Linkify does, if set and the policy
+	// allows a elements with href attributes, links are written with rel="nofollow" as Linkify writes them.
+	// Text already inside a link is left unchanged.
+	Linkify *LinkifyOptions
+
+	// OfficePaste removes the list bullets and other text which office applications hide from browsers
+	// with conditional comments such as <![if !supportLists]>, see OfficePastePolicy.
+	OfficePaste bool
+
+	// ConditionalComments keeps the conditional comments read by Outlook, such as <!--[if mso]>...<![endif]-->,
+	// with their content sanitized by the policy and the VML elements used for Outlook fallbacks allowed,
+	// and downlevel-revealed comments such as <!--[if !mso]><!-->, see EmailPolicy. Other comments are removed.
+	ConditionalComments bool
+
+	// RemoveEmpty removes elements with no attributes which contain only whitespace, such as <p></p> or <b> </b>,
+	// after other tags and attributes have been removed. Void elements such as img and table cells are kept.
+	RemoveEmpty bool
+
+	// Containment removes list items and table elements which are not inside a valid parent, such as
+	// an li outside ul or ol, or a td outside tr, keeping their content, so that sanitized fragments
+	// cannot break the layout of the page they are inserted into. Stray list items become paragraphs.
+	Containment bool
+
+	// CollapseFormatting removes formatting tags such as b or em which are nested inside the same tag,
+	// so that <b><b>x</b></b> becomes <b>x</b>.
+	CollapseFormatting bool
+
+	// HeadingIDs gives h1 to h6 elements without an id an id made from their text by Path,
+	// with -2, -3 and so on appended to ids already used, so that headings may be linked to.
+	HeadingIDs bool
+
+	// StructuredData keeps script elements of type application/ld+json which contain valid json, with <, > and &
+	// escaped so that the json cannot close the element, and allows the microdata attributes itemscope, itemtype,
+	// itemprop, itemid and itemref, so that markup read by search engines is kept. Invalid json is removed.
+	StructuredData bool
+
+	// NormalizeInput removes a byte order mark and replaces \r\n and \r line endings with \n before sanitizing,
+	// so that output is the same whichever platform the input was submitted from.
+	NormalizeInput bool
+
+	// ReplaceInvalid replaces NUL bytes and invalid utf-8 in the input with U+FFFD, by default they are removed.
+	ReplaceInvalid bool
+
+	// XHTML writes void elements such as br as <br/> and never writes end tags for them,
+	// so that output may be embedded in xml. See Compat4 for how void elements are written otherwise.
+	XHTML bool
+
+	// MaxTokenLength limits the bytes buffered for a single tag, comment or run of text, 16MB if it is zero,
+	// or no limit if it is negative. Longer tokens stop sanitizing with html.ErrBufferExceeded,
+	// bounding the memory used by hostile input.
+	MaxTokenLength int
+
+	// PartialOutput returns the html sanitized before an error stopped sanitizing, with the error.
+	// By default an empty string is returned with the error, so long documents with one bad token are lost.
+	// Elements left open by the error are closed if Compat is Compat2 or later.
+	PartialOutput bool
+
+	// CSP removes style attributes and script elements, and style elements unless Nonce is set,
+	// so that output is compatible with a Content-Security-Policy which does not allow unsafe-inline.
+	// Event handlers and javascript: urls are always removed. Nonce attributes in the input are removed.
+	CSP bool
+
+	// Nonce is set as the nonce attribute of style elements kept if CSP is set,
+	// it must be the nonce sent in the Content-Security-Policy header of the page.
+	// Style elements which could load resources, with @import, url() or similar, are removed,
+	// as the nonce would allow them to request urls such as those used to read attribute values.
+	Nonce string
+
+	// Audit is called for each script element, event handler or script url removed, if set,
+	// with the html it was removed from, for example to log attempts to add scripts.
+	Audit func(AuditEvent)
+
+	// Metrics receives the time taken to sanitize each input and the elements and attributes removed, if set.
+	Metrics Metrics
+
+	// Compat selects the output for html which has been sanitized differently between versions,
+	// by default Compat1 so that html stored by earlier versions may be reproduced exactly.
+	Compat CompatLevel
+}
+
+// CompatLevel selects a set of changes to sanitized output, each level includes the changes of the levels before it.
+// Levels are only added for changes which would alter stored html, fixes for unsafe output apply at every level.
+type CompatLevel int
+
+const (
+	// Compat1 writes html as earlier versions did, so that HTMLAllowing produces the same output as before.
+	Compat1 CompatLevel = iota
+
+	// Compat2 closes elements left open, so that <b>bold becomes <b>bold</b>.
+	// Elements which end implicitly such as li and p are closed before the next li or p,
+	// and elements left open inside an element are closed before its end tag.
+	Compat2
+
+	// Compat3 removes end tags which do not close an open element, such as </div> without <div>,
+	// so that sanitized html cannot close elements of the page it is inserted into.
+	Compat3
+
+	// Compat4 writes void elements as <br> however they were written, and never writes end tags for them,
+	// and writes an end tag after other elements written as self closing, so that <p/> becomes <p></p>.
+	// Script, style and other raw text elements written as self closing, such as <script/>,
+	// are removed with the text which follows them, as browsers read that text as their content.
+	Compat4
+
+	// CompatLatest is the most recent level, output using it may change in future versions.
+	CompatLatest = Compat4
+)
+
+// The policy used by HTMLAllowing and Sanitize, set by SetDefaultPolicy.
+var defaultPolicy struct {
+	sync.RWMutex
+	policy *Policy
+}
+
+// SetDefaultPolicy sets the policy used by HTMLAllowing and Sanitize, which may be adjusted by their
+// arguments or options, so that applications may configure sanitizing once at startup.
+// HTML removes all tags and does not use the policy. Setting a nil policy restores the default tags and attributes.
+// The policy must not be modified after it is set. SetDefaultPolicy is safe for concurrent use.
+func SetDefaultPolicy(p *Policy) {
+	defaultPolicy.Lock()
+	defaultPolicy.policy = p
+	defaultPolicy.Unlock()
+}
+
+// DefaultPolicy returns a copy of the policy used by Sanitize, which allows the default tags and attributes
+// unless set by SetDefaultPolicy. HTMLAllowing uses the tags and attributes allowed by earlier versions instead,
+// unless a policy is set by SetDefaultPolicy.
+func DefaultPolicy() *Policy {
+	defaultPolicy.RLock()
+	p := defaultPolicy.policy
+	defaultPolicy.RUnlock()
+	if p == nil {
+		return &Policy{Tags: defaultTags, Attributes: defaultAttributes, URLs: hrefOptions}
+	}
+	c := *p
+	return &c
+}
+
+// legacyPolicy returns the policy used by HTMLAllowing, the policy set by SetDefaultPolicy if any,
+// or one allowing the tags and attributes allowed by earlier versions.
+func legacyPolicy() *Policy {
+	defaultPolicy.RLock()
+	p := defaultPolicy.policy
+	defaultPolicy.RUnlock()
+	if p == nil {
+		return &Policy{Tags: legacyTags, Attributes: legacyAttributes, URLs: hrefOptions}
+	}
+	c := *p
+	return &c
+}
+
+// Attributes which may be set without a value, such as <video controls>.
+var booleanAttributes = []string{"controls", "default", "loop", "muted", "playsinline", "reversed", "open", "itemscope"}
+
+// Attributes allowed by Policy.StructuredData.
+var microdataAttributes = []string{"itemscope", "itemtype", "itemprop", "itemid", "itemref"}
+
+// Elements whose content is read as text by the tokenizer, up to their end tag.
+var rawTextTags = []string{"iframe", "noembed", "noframes", "noscript", "plaintext", "script", "style", "textarea", "title", "xmp"}
+
+// Elements which have no content or end tag in html.
+var voidTags = []string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"}
+
+// Sanitize parses html and removes all tags and attributes not allowed by the policy.
+func (p *Policy) Sanitize(s string) (string, error) {
+	return p.sanitize(s, nil)
+}
+
+// sanitize sanitizes html as Sanitize does, recording the elements and attributes removed in report if not nil,
+// and passing them to the metrics and audit func of the policy if set.
+func (p *Policy) sanitize(s string, report *Report) (string, error) {
+	if p.Metrics == nil && p.Audit == nil {
+		return p.sanitizeTokens(s, report)
+	}
+
+	if report == nil {
+		report = &Report{}
+	}
+	start := time.Now()
+	output, err := p.sanitizeTokens(s, report)
+	if p.Metrics != nil {
+		p.Metrics.Sanitized(time.Since(start), err)
+		for _, r := range report.Removed {
+			p.Metrics.Removed(r)
+		}
+	}
+	return output, err
+}
+
+// sanitizeTokens sanitizes html token by token, recording the elements and attributes removed in report if not nil.
+func (p *Policy) sanitizeTokens(s string, report *Report) (result string, err error) {
+	defer func() {
+		if recover() != nil {
+			result, err = "", ErrSanitizeFailed
+		}
+	}()
+
+	if p.NormalizeInput {
+		s = normalizeInput(s)
+	}
+
+	// Parse the html, after removing bytes which could be read differently by later checks or storage
+	tokenizer := parser.NewTokenizer(strings.NewReader(cleanUTF8(s, p.ReplaceInvalid)))
+	maxTokenLength := p.MaxTokenLength
+	if maxTokenLength == 0 {
+		maxTokenLength = defaultMaxTokenLength
+	}
+	if maxTokenLength > 0 {
+		tokenizer.SetMaxBuf(maxTokenLength)
+	}
+
+	var audit *auditor
+	if p.Audit != nil {
+		audit = &auditor{audit: p.Audit, report: report}
+	}
+
+	var output []outputToken
+	// Names of elements and attributes inside svg and math are case sensitive
+	var foreign foreignContent
+
+	// Elements removed with their content, innermost last, text is not written while any are open
+	var ignore []string
+	links := 0
+	hidden := false
+
+	// The start tag and text of a style element given a nonce, written at its end tag if the stylesheet is safe
+	var style *parser.Token
+	styleText := ""
+
+	// The start tag and text of a json-ld script element, written at its end tag if the json is valid
+	var jsonld *parser.Token
+	jsonText := ""
+
+	for {
+		tokenType := tokenizer.Next()
+		audit.read(tokenizer)
+		token := tokenizer.Token()
+
+		// The tokenizer reads the content of raw text elements as text even if the tag is self closing,
+		// as browsers do, so <script/> starts a script which is removed with its content
+		if tokenType == parser.SelfClosingTagToken && includes(rawTextTags, token.Data) && p.Compat >= Compat4 {
+			tokenType = parser.StartTagToken
+			token.Type = parser.StartTagToken
+		}
+		audit.token(token)
+
+		if tokenType == parser.StartTagToken || tokenType == parser.SelfClosingTagToken || tokenType == parser.EndTagToken {
+			token.Data = foreign.name(token.Data)
+			token.Attr = foreign.attributes(token.Data, token.Attr)
+		}
+
+		switch tokenType {
+
+		case parser.ErrorToken:
+			audit.finish()
+			err := tokenizer.Err()
+			if err == io.EOF {
+				return p.render(output), nil
+			}
+			if p.PartialOutput {
+				return p.render(output), err
+			}
+			return "", err
+
+		case parser.StartTagToken:
+
+			if token.Data == "a" {
+				links++
+			}
+
+			if len(ignore) == 0 && p.StructuredData && isJSONLD(token) {
+				token.Attr = []parser.Attribute{{Key: "type", Val: jsonLDType}}
+				jsonld = &token
+				jsonText = ""
+				continue
+			}
+
+			if len(ignore) == 0 && p.allowed(token.Data) {
+				token.Attr = p.cleanAttributes(token.Data, token.Attr, report)
+				if p.CSP && token.Data == "style" {
+					token.Attr = append(token.Attr, parser.Attribute{Key: "nonce", Val: p.Nonce})
+					style = &token
+					styleText = ""
+					continue
+				}
+				if p.XHTML && includes(voidTags, token.Data) {
+					token.Type = parser.SelfClosingTagToken
+				}
+				foreign.start(token.Data)
+				output = append(output, outputToken{Token: token})
+			} else {
+				// Scripts inside elements removed with their content are reported, unlike other elements
+				if len(ignore) == 0 || token.Data == "script" {
+					report.remove(token.Data, nil)
+				}
+				p.reportScriptAttributes(token.Data, token.Attr, report)
+				ignore = ignoreElement(ignore, token.Data)
+			}
+
+		case parser.SelfClosingTagToken:
+
+			if len(ignore) == 0 && p.allowed(token.Data) {
+				token.Attr = p.cleanAttributes(token.Data, token.Attr, report)
+				if p.XHTML || p.Compat < Compat4 {
+					output = append(output, outputToken{Token: token})
+				} else if includes(voidTags, token.Data) {
+					// In html void elements are written as <br>
+					token.Type = parser.StartTagToken
+					output = append(output, outputToken{Token: token})
+				} else {
+					// In html <div/> opens an element, so write an end tag as the author intended
+					token.Type = parser.StartTagToken
+					end := parser.Token{Type: parser.EndTagToken, DataAtom: token.DataAtom, Data: token.Data}
+					output = append(output, outputToken{Token: token}, outputToken{Token: end})
+				}
+			} else if len(ignore) == 0 {
+				report.remove(token.Data, nil)
+				p.reportScriptAttributes(token.Data, token.Attr, report)
+			} else {
+				// In html <object/> inside an ignored element opens another element to ignore
+				p.reportScriptAttributes(token.Data, token.Attr, report)
+				ignore = ignoreElement(ignore, token.Data)
+			}
+
+		case parser.EndTagToken:
+			if token.Data == "a" && links > 0 {
+				links--
+			}
+
+			if jsonld != nil && token.Data == "script" {
+				if data, ok := cleanJSONLD(jsonText); ok {
+					token.Attr = []parser.Attribute{}
+					text := parser.Token{Type: parser.TextToken, Data: data}
+					output = append(output, outputToken{Token: *jsonld}, outputToken{Token: text, html: data}, outputToken{Token: token})
+				} else {
+					report.remove("script", nil)
+				}
+				jsonld = nil
+				continue
+			}
+
+			if style != nil && token.Data == "style" {
+				// The nonce allows the stylesheet to run, so it is only kept if it cannot load resources.
+				// Styles are raw text, which cannot contain an end tag for style. Inside svg or math a style element
+				// is not raw text, so < is written as a css escape to prevent the text being read as tags.
+				if safeStylesheet(styleText) {
+					token.Attr = []parser.Attribute{}
+					text := parser.Token{Type: parser.TextToken, Data: styleText}
+					output = append(output, outputToken{Token: *style}, outputToken{Token: text, html: strings.ReplaceAll(styleText, "<", `\3c `)}, outputToken{Token: token})
+				} else {
+					report.remove("style", nil)
+				}
+				style = nil
+				continue
+			}
+
+			if len(ignore) == 0 && p.allowed(token.Data) {
+				// Void elements have no end tag
+				if includes(voidTags, token.Data) && (p.XHTML || p.Compat >= Compat4) {
+					continue
+				}
+				token.Attr = []parser.Attribute{}
+				foreign.end(token.Data)
+				output = append(output, outputToken{Token: token})
+			} else {
+				ignore = closeIgnored(ignore, token.Data)
+			}
+
+		case parser.TextToken:
+			// We allow text content through, unless ignoring this entire tag and its contents (including other tags)
+			if jsonld != nil {
+				jsonText += token.Data
+			} else if style != nil {
+				styleText += token.Data
+			} else if len(ignore) == 0 && !hidden {
+				if p.Text != nil {
+					token.Data = p.Text(token.Data)
+				}
+				if p.Linkify != nil && links == 0 && p.allowed("a") && p.allowedAttribute("href") {
+					output = append(output, outputToken{Token: token, html: Linkify(token.Data, *p.Linkify)})
+				} else {
+					output = append(output, outputToken{Token: token})
+				}
+			}
+		case parser.CommentToken:
+			// We ignore comments by default, but downlevel revealed conditional comments hide the text between them
+			if p.ConditionalComments {
+				if len(ignore) > 0 {
+					continue
+				}
+				if comment, ok := p.conditionalComment(token.Data, report); ok {
+					output = append(output, outputToken{Token: token, html: comment})
+				}
+			} else if p.OfficePaste && strings.HasPrefix(token.Data, "[if") && !strings.Contains(token.Data, "[endif]") {
+				hidden = true
+			} else if p.OfficePaste && strings.HasPrefix(token.Data, "[endif") {
+				hidden = false
+			}
+		case parser.DoctypeToken:
+			// We ignore doctypes by default - html5 does not require them and this is intended for sanitizing snippets of text
+		default:
+			// We ignore unknown token types by default
+
+		}
+
+	}
+
+}
+
+// ignoreElement adds tag to the elements being removed with their content if it is one of ignoreTags.
+// Void elements such as embed have no content, so are not added.
+func ignoreElement(ignore []string, tag string) []string {
+	if includes(ignoreTags, tag) && !includes(voidTags, tag) {
+		return append(ignore, tag)
+	}
+	return ignore
+}
+
+// closeIgnored removes the innermost element being removed with their content named tag,
+// and any opened inside it, returning the elements still open.
+func closeIgnored(ignore []string, tag string) []string {
+	for i := len(ignore) - 1; i >= 0; i-- {
+		if ignore[i] == tag {
+			return ignore[:i]
+		}
+	}
+	return ignore
+}
+
+// reportScriptAttributes records the event handlers and script urls on an element which is removed,
+// so that they are reported and audited although the attributes of removed elements are not otherwise checked.
+func (p *Policy) reportScriptAttributes(tag string, a []parser.Attribute, report *Report) {
+	if report == nil {
+		return
+	}
+	for i, attr := range a {
+		url := includes(urlAttributes, attr.Key) || includes(p.URLAttributes, attr.Key) || includes(reportURLAttributes, attr.Key)
+		if !(Removal{Tag: tag, Attribute: attr.Key, Value: attr.Val}).Script() {
+			continue
+		}
+		if url && !isEventHandler(attr.Key) {
+			report.removeURL(tag, &a[i])
+		} else {
+			report.remove(tag, &a[i])
+		}
+	}
+}
+
+// Attributes removed by Policy.CSP.
+var cspAttributes = []string{"style", "nonce"}
+
+// allowed reports whether the policy keeps elements with tag.
+func (p *Policy) allowed(tag string) bool {
+	if p.CSP && (tag == "script" || tag == "style" && p.Nonce == "") {
+		return false
+	}
+	return includes(p.Tags, tag)
+}
+
+// allowedAttribute reports whether the policy keeps attributes named key, if their values are valid.
+func (p *Policy) allowedAttribute(key string) bool {
+	return includes(p.Attributes, key) || p.StructuredData && includes(microdataAttributes, key)
+}
+
+// cleanAttributes returns an array of attributes of an element after removing malicious ones,
+// recording those removed in report if not nil. Event handlers such as onclick and srcdoc,
+// which holds a document for an iframe, are always removed.
+func (p *Policy) cleanAttributes(tag string, a []parser.Attribute, report *Report) []parser.Attribute {
+	if len(a) == 0 {
+		return a
+	}
+
+	var cleaned []parser.Attribute
+	for i, attr := range a {
+		if !p.allowedAttribute(attr.Key) || isEventHandler(attr.Key) || attr.Key == "srcdoc" || p.CSP && includes(cspAttributes, attr.Key) {
+			report.remove(tag, &attr)
+		} else {
+
+			val := strings.ToLower(attr.Val)
+
+			// Check for illegal attribute values, including those hidden with entities, escapes or whitespace
+			if illegalAttr.FindString(val) != "" || illegalAttr.FindString(unobfuscate(attr.Val)) != "" {
+				attr.Val = ""
+			}
+
+			// Check for legal href values - / mailto:// http:// or https://
+			// unless the policy sets the schemes allowed, which are checked by URL below
+			if attr.Key == "href" && len(p.URLs.Schemes) == 0 && legalHrefAttr.FindString(val) == "" {
+				attr.Val = ""
+			}
+
+			// Each candidate in a srcset and each url in ping is checked, attributes with a fixed syntax must be valid
+			if attr.Key == "srcset" {
+				attr.Val = cleanSrcset(attr.Val, p.URLs)
+			} else if attr.Key == "ping" || attr.Key == "itemtype" {
+				attr.Val = cleanURLList(attr.Val, p.URLs)
+			} else if valid, ok := attributeValidators[attr.Key]; ok && !valid(attr.Val) {
+				attr.Val = ""
+			} else if valid, ok := namespaceValidators[attr.Key]; ok && (includes(foreignTags, tag) || includes(vmlTags, tag)) && !valid(attr.Val) {
+				attr.Val = ""
+			}
+
+			// Restrict styles to the properties allowed by the policy
+			if attr.Key == "style" && len(p.StyleProperties) > 0 {
+				attr.Val = cleanStyle(attr.Val, p.StyleProperties)
+			}
+
+			// Check attributes restricted by the policy
+			if pattern, ok := p.AttributePatterns[attr.Key]; ok && !pattern.MatchString(attr.Val) {
+				attr.Val = ""
+			}
+
+			// Normalise the urls we keep, removing those not allowed
+			url := includes(urlAttributes, attr.Key) || includes(p.URLAttributes, attr.Key)
+			if attr.Val != "" && url {
+				if u, err := URL(attr.Val, p.URLs); err == nil {
+					attr.Val = u
+				} else {
+					attr.Val = ""
+				}
+			}
+
+			// If we still have an attribute, append it to the array
+			if attr.Val != "" || (val == "" && includes(booleanAttributes, attr.Key)) {
+				cleaned = appendAttribute(cleaned, attr)
+			} else if url || includes(reportURLAttributes, attr.Key) {
+				report.removeURL(tag, &a[i])
+			} else {
+				report.remove(tag, &a[i])
+			}
+		}
+	}
+	return cleaned
+}
+
+var (
+	// Descriptors of image candidates in srcset, such as 100w or 1.5x
+	legalSrcsetDescriptor = regexp.MustCompile(`\A(\d+w|\d+(\.\d+)?x)?\z`)
+
+	// Values of colspan and rowspan, from 1 to 999
+	legalSpan = regexp.MustCompile(`\A[1-9][0-9]{0,2}\z`)
+
+	// Values of width and height, such as 100, 100px or 50%
+	legalDimension = regexp.MustCompile(`(?i)\A[0-9]{1,5}(px|%)?\z`)
+
+	// Values of tabindex, such as 0 or -1
+	legalTabIndex = regexp.MustCompile(`\A-?[0-9]{1,5}\z`)
+
+	// Values of itemprop and itemref, space separated names or urls
+	legalNames = regexp.MustCompile(`\A[\w.:/#-]+(\s+[\w.:/#-]+)*\z`)
+
+	// Values of sizes and media, such as (max-width: 600px) 480px, 100vw
+	legalMedia = regexp.MustCompile(`(?i)\A[a-z0-9\s().,:%+*/-]*\z`)
+)
+
+// Values of scope on table headers.
+var tableScopes = []string{"row", "col", "rowgroup", "colgroup"}
+
+// Values of dir.
+var textDirections = []string{"ltr", "rtl", "auto"}
+
+// Validators for attributes with a fixed syntax, these attributes are removed if their value is not valid.
+var attributeValidators = map[string]func(string) bool{
+	"colspan":  legalSpan.MatchString,
+	"datetime": legalDatetime.MatchString,
+	"dir":      func(v string) bool { return includes(textDirections, strings.ToLower(v)) },
+	"height":   legalDimension.MatchString,
+	"itemprop": legalNames.MatchString,
+	"itemref":  legalNames.MatchString,
+	"lang":     legalLang,
+	"media":    legalMedia.MatchString,
+	"rowspan":  legalSpan.MatchString,
+	"scope":    func(v string) bool { return includes(tableScopes, strings.ToLower(v)) },
+	"sizes":    legalMedia.MatchString,
+	"tabindex": legalTabIndex.MatchString,
+	"width":    legalDimension.MatchString,
+	"xml:lang": legalLang,
+}
+
+// Validators for namespace declarations on svg, math and vml elements, which may only declare the namespaces they use.
+// Namespace declarations on other elements, such as those in xml documents, are not checked.
+var namespaceValidators = map[string]func(string) bool{
+	"xmlns":       func(v string) bool { return includes(foreignNamespaces, v) },
+	"xmlns:xlink": func(v string) bool { return includes(foreignNamespaces, v) },
+	"xmlns:o":     func(v string) bool { return includes(vmlNamespaces, v) },
+	"xmlns:v":     func(v string) bool { return includes(vmlNamespaces, v) },
+	"xmlns:w":     func(v string) bool { return includes(vmlNamespaces, v) },
+}
+
+// isEventHandler reports whether an attribute is an event handler such as onclick, which are never allowed.
+func isEventHandler(key string) bool {
+	return strings.HasPrefix(strings.ToLower(key), "on")
+}
+
+// legalLang reports whether s is a well formed BCP 47 language tag, such as en or zh-Hant-TW.
+func legalLang(s string) bool {
+	if strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-") != "" {
+		return false
+	}
+	_, err := language.Parse(s)
+	return err == nil
+}
+
+// cleanSrcset returns the candidates in a srcset with urls allowed by opts, normalised using URL,
+// removing candidates with other urls or invalid descriptors.
+func cleanSrcset(srcset string, opts URLOptions) string {
+	var candidates []string
+	s := srcset
+	for {
+		s = strings.TrimLeft(s, ", \t\n\r\f")
+		if s == "" {
+			break
+		}
+
+		// The url runs to the next whitespace, a trailing comma ends the candidate
+		end := strings.IndexAny(s, " \t\n\r\f")
+		if end == -1 {
+			end = len(s)
+		}
+		u, descriptor := s[:end], ""
+		s = s[end:]
+		if strings.HasSuffix(u, ",") {
+			u = strings.TrimRight(u, ",")
+		} else {
+			end = strings.Index(s, ",")
+			if end == -1 {
+				end = len(s)
+			}
+			descriptor = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+
+		if !legalSrcsetDescriptor.MatchString(descriptor) {
+			continue
+		}
+		u, err := URL(u, opts)
+		if err != nil {
+			continue
+		}
+		if descriptor != "" {
+			u += " " + descriptor
+		}
+		candidates = append(candidates, u)
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// Escapes which may hide a scheme from illegalAttr, such as \u006a, \x6a or the css escape \6a
+var attributeEscapes = regexp.MustCompile(`\\(u[0-9a-fA-F]{4}|u\{[0-9a-fA-F]{1,6}\}|x[0-9a-fA-F]{2}|[0-9a-fA-F]{1,6}\s?)`)
+
+// unobfuscate returns an attribute value with html entities decoded repeatedly, javascript and css escapes decoded,
+// and whitespace, control and format characters removed, in lowercase, so that it may be checked for schemes
+// such as javascript: which have been hidden, for example as &amp;#106;avascript: or java&#x09;script:.
+func unobfuscate(s string) string {
+	// Entities may have been encoded more than once
+	for i := 0; i < 3 && strings.Contains(s, "&"); i++ {
+		s = html.UnescapeString(s)
+	}
+
+	s = attributeEscapes.ReplaceAllStringFunc(s, func(e string) string {
+		hex := strings.Trim(strings.TrimSpace(e[1:]), "ux{}")
+		r, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return ""
+		}
+		return string(rune(r))
+	})
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || unicode.In(r, unicode.Cf) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// cleanURLList returns the urls in a space separated list allowed by opts, normalised using URL.
+func cleanURLList(list string, opts URLOptions) string {
+	var urls []string
+	for _, s := range strings.Fields(list) {
+		if u, err := URL(s, opts); err == nil {
+			urls = append(urls, u)
+		}
+	}
+	return strings.Join(urls, " ")
+}
+
+// appendAttribute appends attr to attributes unless an attribute with the same key is present.
+// Browsers use the first occurrence of a repeated attribute, so writing a later one as well could
+// change the meaning of the output. The html tokenizer already drops repeated attributes, xml does not.
+func appendAttribute(attributes []parser.Attribute, attr parser.Attribute) []parser.Attribute {
+	for _, a := range attributes {
+		if a.Key == attr.Key && a.Namespace == attr.Namespace {
+			return attributes
+		}
+	}
+	return append(attributes, attr)
+}
+
+// FeedPolicy returns a policy for the html content of rss and atom feeds, such as content:encoded or summary.
+// The tags allowed follow the safe list used by feed validators, without scripts, forms or embedded content.
+// Links and images must use absolute http or https urls, and void elements are written as <br/>
+// so that the output is well formed for embedding in xml. Text is escaped using only &amp;, &lt;, &gt;
+// and numeric character references, which xml defines, other entities such as &nbsp; are written as characters.
+func FeedPolicy() *Policy {
+	return &Policy{
+		Tags: []string{
+			"a", "abbr", "acronym", "address", "b", "big", "blockquote", "br", "caption", "cite", "code",
+			"dd", "del", "dfn", "div", "dl", "dt", "em", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img",
+			"ins", "kbd", "li", "ol", "p", "pre", "q", "s", "samp", "small", "span", "strike", "strong", "sub", "sup",
+			"table", "tbody", "td", "tfoot", "th", "thead", "tr", "tt", "u", "ul", "var",
+		},
+		Attributes:    []string{"href", "src", "alt", "title", "cite", "datetime", "width", "height", "colspan", "rowspan", "lang", "dir"},
+		URLAttributes: []string{"src"},
+		URLs:          URLOptions{Schemes: []string{"http", "https", "mailto"}, RejectConfusable: true},
+		XHTML:         true,
+	}
+}
+
+// MediaPolicy returns a policy for embedded audio and video, allowing video, audio, source and track elements
+// with links as fallback content. Media urls must use http or https, or be relative, and autoplay is removed.
+// Iframes are not allowed, so media must be hosted as files rather than embedded from other sites.
+func MediaPolicy() *Policy {
+	return &Policy{
+		Tags: []string{"video", "audio", "source", "track", "a"},
+		Attributes: []string{
+			"src", "poster", "href", "type", "controls", "loop", "muted", "playsinline", "preload",
+			"width", "height", "kind", "srclang", "label", "default",
+		},
+		URLAttributes: []string{"src"},
+		URLs:          URLOptions{Schemes: []string{"http", "https"}, AllowRelative: true, RejectConfusable: true},
+	}
+}
+
+// Classes which mark the language of code blocks for syntax highlighters, such as language-go
+var languageClass = regexp.MustCompile(`\Alanguage-[\w+-]+\z`)
+
+// CodePolicy returns a policy for comments on developer sites, allowing code blocks such as
+// <pre><code class="language-go"> with the class used by syntax highlighters, along with paragraphs,
+// lists, links and emphasis. Whitespace inside code blocks is preserved, and other classes are removed.
+func CodePolicy() *Policy {
+	return &Policy{
+		Tags:              []string{"pre", "code", "p", "br", "a", "strong", "em", "b", "i", "ul", "ol", "li", "blockquote"},
+		Attributes:        []string{"href", "class"},
+		AttributePatterns: map[string]*regexp.Regexp{"class": languageClass},
+		URLs:              hrefOptions,
+	}
+}
+
+// TablePolicy returns a policy for tables in user content such as wiki pages, allowing table elements
+// with colspan and rowspan of up to 999 and scope on headers, along with simple formatting and links in cells.
+// Table rows and cells outside a table are removed, see Policy.Containment.
+func TablePolicy() *Policy {
+	return &Policy{
+		Tags: []string{
+			"table", "caption", "colgroup", "col", "thead", "tbody", "tfoot", "tr", "th", "td",
+			"p", "br", "a", "strong", "em", "b", "i", "code",
+		},
+		Attributes:  []string{"href", "colspan", "rowspan", "scope", "lang", "dir"},
+		URLs:        hrefOptions,
+		Containment: true,
+	}
+}
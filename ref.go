@@ -0,0 +1,65 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The maximum lengths of git refs and docker tags
+const (
+	maxGitRef    = 255
+	maxDockerTag = 128
+)
+
+var (
+	// Characters git does not allow in refs, and others which need quoting in shells
+	illegalGitRef = regexp.MustCompile(`[^A-Za-z0-9/._-]+`)
+
+	// Characters docker does not allow in tags
+	illegalDockerTag = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+	// Repeated separators in refs and tags
+	repeatedSeparators = regexp.MustCompile(`-{2,}|\.{2,}|/{2,}`)
+)
+
+// GitRef makes a git branch or tag name from s, such as a title, following the rules of git check-ref-format.
+// Accents are transliterated, spaces and other characters are replaced with -, and .. @{ and
+// repeated slashes are removed. Path components may not start with . or - or end with .lock,
+// and the name may not end with / or . and is at most 255 bytes. It may return an empty string.
+func GitRef(s string) string {
+	ref := illegalGitRef.ReplaceAllString(Accents(strings.TrimSpace(s)), "-")
+	ref = repeatedSeparators.ReplaceAllStringFunc(ref, func(m string) string { return m[:1] })
+
+	var components []string
+	for _, c := range strings.Split(ref, "/") {
+		for strings.HasSuffix(c, ".lock") {
+			c = strings.TrimSuffix(c, ".lock")
+		}
+		c = strings.Trim(c, ".-")
+		if c != "" {
+			components = append(components, c)
+		}
+	}
+	ref = strings.Join(components, "/")
+
+	if len(ref) > maxGitRef {
+		ref = strings.TrimRight(ref[:maxGitRef], "./-")
+	}
+	if ref == "@" || ref == "HEAD" {
+		return ""
+	}
+	return ref
+}
+
+// DockerTag makes a docker image tag from s, such as a branch name, containing only letters, digits, _ . and -,
+// starting with a letter, digit or _, and at most 128 characters. Accents are transliterated
+// and other characters are replaced with -. It may return an empty string.
+func DockerTag(s string) string {
+	tag := illegalDockerTag.ReplaceAllString(Accents(strings.TrimSpace(s)), "-")
+	tag = repeatedSeparators.ReplaceAllStringFunc(tag, func(m string) string { return m[:1] })
+	tag = strings.TrimLeft(tag, ".-")
+	if len(tag) > maxDockerTag {
+		tag = strings.TrimRight(tag[:maxDockerTag], ".-")
+	}
+	return tag
+}
@@ -0,0 +1,48 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var gitRefTests = []Test{
+	{"Fix login bug", `Fix-login-bug`},
+	{"feature/Add café menu", `feature/Add-cafe-menu`},
+	{"--force", `force`},
+	{"a..b~1^2:c?*[d]\\e", `a.b-1-2-c-d-e`},
+	{"release//v1.0/", `release/v1.0`},
+	{".hidden/config.lock", `hidden/config`},
+	{"branch@{upstream}", `branch-upstream`},
+	{"tab\tand\x00null", `tab-and-null`},
+	{"HEAD", ``},
+	{"@", ``},
+	{strings.Repeat("a", 300), strings.Repeat("a", 255)},
+}
+
+func TestGitRef(t *testing.T) {
+	for _, test := range gitRefTests {
+		output := GitRef(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var dockerTagTests = []Test{
+	{"v1.2.3", `v1.2.3`},
+	{"feature/new login", `feature-new-login`},
+	{"-rc..1", `rc.1`},
+	{".hidden", `hidden`},
+	{"Übersicht_2024", `UEbersicht_2024`},
+	{"!!!", ``},
+	{strings.Repeat("b", 200), strings.Repeat("b", 128)},
+}
+
+func TestDockerTag(t *testing.T) {
+	for _, test := range dockerTagTests {
+		output := DockerTag(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+
+	parser "golang.org/x/net/html"
+)
+
+// Classes of elements which hold quoted messages or signatures in replies sent by common email clients.
+var quoteClasses = []string{"gmail_quote", "gmail_signature", "yahoo_quoted", "moz-cite-prefix", "moz-signature"}
+
+// Ids of elements which start the quoted message in replies sent by Outlook, removed with the rest of the message.
+var replyHeaderIDs = []string{"divRplyFwdMsg", "appendonsend"}
+
+// Lines introducing a quoted message, such as On Mon, 1 Jan 2024, Jane <jane@example.com> wrote:
+var replyAttribution = regexp.MustCompile(`(?is)\Aon\s.*\swrote:\z`)
+
+// StripQuotedReply removes the quoted message from the html of an email reply, so that only the new message
+// is kept, for example when storing replies to support tickets. Elements used by email clients for quotes and
+// signatures such as <blockquote class="gmail_quote"> or <blockquote type="cite"> are removed with their content,
+// and everything from a line such as On ... wrote: or a -- signature delimiter to the end of the message.
+// Elements left open by removing the end of the message are closed. The html is not sanitized,
+// so the result must still be sanitized before display.
+func StripQuotedReply(html string) string {
+	tokenizer := parser.NewTokenizer(strings.NewReader(html))
+	b := strings.Builder{}
+
+	// Elements open in the output, and those open at the start of the current line
+	var open, lineOpen []string
+	lineStart := 0
+	line := ""
+
+	// The element being removed with its content, and the depth of elements with the same name inside it
+	skip := ""
+	depth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		raw := string(tokenizer.Raw())
+		token := tokenizer.Token()
+
+		if skip != "" && tokenType != parser.ErrorToken {
+			if tokenType == parser.StartTagToken && token.Data == skip {
+				depth++
+			} else if tokenType == parser.EndTagToken && token.Data == skip {
+				depth--
+			}
+			if depth == 0 {
+				skip = ""
+			}
+			continue
+		}
+
+		// At the end of each line check whether it starts the quoted message or a signature
+		boundary := tokenType == parser.ErrorToken || (tokenType != parser.TextToken && includes(blockTags, token.Data))
+		if boundary {
+			line = strings.TrimSpace(line)
+			if replyAttribution.MatchString(line) || line == "--" {
+				return closeElementsOpen(b.String()[:lineStart], lineOpen)
+			}
+		}
+
+		switch tokenType {
+		case parser.ErrorToken:
+			return b.String()
+
+		case parser.StartTagToken:
+			if includes(replyHeaderIDs, attribute(token.Attr, "id")) {
+				return closeElementsOpen(b.String(), open)
+			}
+			if quotedElement(token) {
+				skip = token.Data
+				depth = 1
+				lineStart = b.Len()
+				lineOpen = append(lineOpen[:0], open...)
+				line = ""
+				continue
+			}
+			if !includes(voidTags, token.Data) {
+				open = append(open, token.Data)
+			}
+
+		case parser.EndTagToken:
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == token.Data {
+					open = open[:i]
+					break
+				}
+			}
+
+		case parser.TextToken:
+			line += token.Data
+		}
+
+		// Lines start before a block element, so that a removed line does not leave it empty, or after a line break
+		blockStart := boundary && tokenType == parser.StartTagToken && !includes(voidTags, token.Data)
+		if blockStart {
+			lineStart = b.Len()
+			lineOpen = append(lineOpen[:0], open[:len(open)-1]...)
+			line = ""
+		}
+		b.WriteString(raw)
+		if boundary && !blockStart {
+			lineStart = b.Len()
+			lineOpen = append(lineOpen[:0], open...)
+			line = ""
+		}
+	}
+}
+
+// quotedElement reports whether t starts an element used by email clients for a quoted message or signature.
+func quotedElement(t parser.Token) bool {
+	if t.Data == "blockquote" && strings.EqualFold(attribute(t.Attr, "type"), "cite") {
+		return true
+	}
+	for _, class := range strings.Fields(attribute(t.Attr, "class")) {
+		if includes(quoteClasses, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// closeElementsOpen appends end tags for the elements open at the end of html, innermost first.
+func closeElementsOpen(html string, open []string) string {
+	b := strings.Builder{}
+	b.WriteString(html)
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// Trailers added to the end of messages by mail apps, such as Sent from my iPhone
+var replyTrailer = regexp.MustCompile(`(?i)\A(sent from my [\w ]+|sent from (mail|yahoo mail|outlook) for [\w ]+|get outlook for [\w ]+)\.?\z`)
+
+// TrimReply removes quoted text from a plain text email reply, so that only the new message is kept.
+// Lines quoted with > are removed, as is everything from a line such as On ... wrote: or a -- signature delimiter
+// to the end of the message, and trailers such as Sent from my iPhone at the end of the message.
+// Line endings are normalised to \n and blank lines at either end are removed, which may leave an empty string.
+func TrimReply(text string) string {
+	lines := strings.Split(NormalizeNewlines(text), "\n")
+
+	var kept []string
+	quoted := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Attributions may be wrapped onto a second line by the mail app
+		attribution := replyAttribution.MatchString(trimmed)
+		if !attribution && i+1 < len(lines) {
+			attribution = replyAttribution.MatchString(trimmed + " " + strings.TrimSpace(lines[i+1]))
+		}
+		if trimmed == "--" || attribution {
+			break
+		}
+
+		// Remove quoted lines, and the blank line left between the text before and after them
+		if strings.HasPrefix(trimmed, ">") {
+			quoted = true
+			continue
+		}
+		if trimmed == "" && quoted && len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+			continue
+		}
+		if trimmed != "" {
+			quoted = false
+		}
+		if trimmed == "" && len(kept) == 0 {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	// Remove trailers and blank lines from the end of the message
+	for len(kept) > 0 {
+		last := strings.TrimSpace(kept[len(kept)-1])
+		if last != "" && !replyTrailer.MatchString(last) {
+			break
+		}
+		kept = kept[:len(kept)-1]
+	}
+
+	return strings.Join(kept, "\n")
+}
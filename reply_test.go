@@ -0,0 +1,50 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var stripQuotedReplyTests = []Test{
+	{`<div dir="ltr">Thanks, that fixed it.</div><br><div class="gmail_quote"><div dir="ltr" class="gmail_attr">On Mon, Jan 1, 2024 at 10:00 AM Support &lt;<a href="mailto:help@example.com">help@example.com</a>&gt; wrote:<br></div><blockquote class="gmail_quote" style="margin:0"><div>Have you tried <div>restarting</div>?</div></blockquote></div>`,
+		`<div dir="ltr">Thanks, that fixed it.</div><br>`},
+	{`<p>New text</p><blockquote class="other gmail_quote">Old</blockquote><p>More</p>`, `<p>New text</p><p>More</p>`},
+	{`<div>Sounds good<br><br>On 1 Jan 2024, at 10:00, Jane &lt;<a href="mailto:jane@example.com">jane@example.com</a>&gt; wrote:<br><blockquote>Old</blockquote></div>`,
+		`<div>Sounds good<br><br></div>`},
+	{`<p>Yes</p><div>On Tuesday, Bob wrote:</div><div>Old message</div>`, `<p>Yes</p>`},
+	{`<div>Cheers<br>-- <br>Jane Smith<br>Acme Inc</div>`, `<div>Cheers<br></div>`},
+	{`<p>Answer</p><div class="gmail_signature"><p>Jane</p></div><p>After</p>`, `<p>Answer</p><p>After</p>`},
+	{`<p>Hi</p><blockquote type="cite"><p>Quoted <blockquote>nested</blockquote></p></blockquote>`, `<p>Hi</p>`},
+	{`<div>Reply</div><div id="appendonsend"></div><hr><div id="divRplyFwdMsg"><b>From:</b> Bob</div><div>Old</div>`, `<div>Reply</div>`},
+	{`<p>On the whole this works - see the --verbose flag. We wrote: tests.</p><blockquote>Quote</blockquote>`, `<p>On the whole this works - see the --verbose flag. We wrote: tests.</p><blockquote>Quote</blockquote>`},
+	{`<p>Hi</p><blockquote type="cite"><p>Unclosed`, `<p>Hi</p>`},
+	{`Plain reply`, `Plain reply`},
+}
+
+func TestStripQuotedReply(t *testing.T) {
+	for _, test := range stripQuotedReplyTests {
+		output := StripQuotedReply(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var trimReplyTests = []Test{
+	{"Thanks, that worked.\r\n\r\nOn Mon, Jan 1, 2024 at 10:00 AM Support <help@example.com> wrote:\r\n> Have you tried restarting?\r\n", "Thanks, that worked."},
+	{"Sounds good.\n\nOn 1 Jan 2024, at 10:00, Jane Smith <\njane@example.com> wrote:\n\n> Old\n", "Sounds good."},
+	{"I agree with this:\n\n> point one\n> point two\n\nbut not with this:\n\n> point three\n\nCheers", "I agree with this:\n\nbut not with this:\n\nCheers"},
+	{"\n\nCall me.\n\n-- \nJane Smith\nAcme Inc\n", "Call me."},
+	{"Yes please  \nthanks\n\nSent from my iPhone\n", "Yes please  \nthanks"},
+	{"Done.\n\nGet Outlook for Android", "Done."},
+	{"On the whole this works, see the --verbose flag.\nWe wrote: tests.", "On the whole this works, see the --verbose flag.\nWe wrote: tests."},
+	{"> only quoted\n", ""},
+}
+
+func TestTrimReply(t *testing.T) {
+	for _, test := range trimReplyTests {
+		output := TrimReply(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
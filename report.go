@@ -0,0 +1,74 @@
+package sanitize
+
+import (
+	parser "golang.org/x/net/html"
+)
+
+// Removal is an element or attribute removed by Policy.SanitizeReport.
+type Removal struct {
+	// Tag is the element removed, or the element the attribute was removed from.
+	Tag string
+
+	// Attribute is the name of the attribute removed, or empty if the element was removed.
+	Attribute string
+
+	// Value is the value of the attribute removed.
+	Value string
+
+	// URL reports whether the attribute held a url which was rejected, such as a javascript: link.
+	URL bool
+}
+
+// EventHandler reports whether the removal is of an event handler attribute such as onclick.
+func (r Removal) EventHandler() bool {
+	return r.Attribute != "" && isEventHandler(r.Attribute)
+}
+
+// Script reports whether the removal is of a script element, an event handler,
+// or an attribute holding a javascript: or data: url, including urls hidden with entities or escapes.
+func (r Removal) Script() bool {
+	if r.Attribute == "" {
+		return r.Tag == "script"
+	}
+	return r.EventHandler() || illegalAttr.MatchString(unobfuscate(r.Value))
+}
+
+// Report lists the elements and attributes removed by Policy.SanitizeReport, in the order they were found.
+// Elements removed with their content, such as iframe, are listed but not the elements inside them,
+// except for script elements. Event handlers and script urls on removed elements are also listed.
+type Report struct {
+	Removed []Removal
+}
+
+// remove records the removal of an element, or of attr from the element if attr is not nil.
+// Removals are not recorded if r is nil.
+func (r *Report) remove(tag string, attr *parser.Attribute) {
+	if r == nil {
+		return
+	}
+	removal := Removal{Tag: tag}
+	if attr != nil {
+		removal.Attribute = attr.Key
+		removal.Value = attr.Val
+	}
+	r.Removed = append(r.Removed, removal)
+}
+
+// Attributes which hold urls, reported as rejected urls when removed even if the policy does not check them with URL.
+var reportURLAttributes = []string{"src", "srcset", "ping", "data"}
+
+// removeURL records the removal of attr from the element because its url was rejected.
+func (r *Report) removeURL(tag string, attr *parser.Attribute) {
+	if r == nil {
+		return
+	}
+	r.Removed = append(r.Removed, Removal{Tag: tag, Attribute: attr.Key, Value: attr.Val, URL: true})
+}
+
+// SanitizeReport sanitizes html as Sanitize does, and returns a report of the elements and attributes removed,
+// for example to warn users that their content was changed or to log attempts to add scripts.
+func (p *Policy) SanitizeReport(s string) (string, *Report, error) {
+	report := &Report{}
+	output, err := p.sanitize(s, report)
+	return output, report, err
+}
@@ -0,0 +1,42 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+func TestSanitizeReport(t *testing.T) {
+	// Event handlers are removed even if allowed by mistake
+	p := &Policy{Tags: []string{"p", "img"}, Attributes: []string{"src", "onerror", "ONLOAD", "title"}, URLs: URLOptions{AllowRelative: true}}
+	input := `<p title="t" onclick="a()">text<script>alert(1)<b>x</b></script><img src="/a.png" onerror="b()" OnLoad="c()"><i>i</i><img src="javascript:d()"></p>`
+	expected := `<p title="t">text<img src="/a.png">i<img></p>`
+	removed := []Removal{
+		{Tag: "p", Attribute: "onclick", Value: "a()"},
+		{Tag: "script"},
+		{Tag: "img", Attribute: "onerror", Value: "b()"},
+		{Tag: "img", Attribute: "onload", Value: "c()"},
+		{Tag: "i"},
+		{Tag: "img", Attribute: "src", Value: "javascript:d()", URL: true},
+	}
+
+	output, report, err := p.SanitizeReport(input)
+	if err != nil || output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+	if len(report.Removed) != len(removed) {
+		t.Fatalf("SanitizeReport(%q) removed %v, want %v", input, report.Removed, removed)
+	}
+	for i, r := range report.Removed {
+		if r != removed[i] {
+			t.Fatalf("SanitizeReport(%q) removed %v, want %v", input, report.Removed, removed)
+		}
+	}
+	if !report.Removed[0].EventHandler() || report.Removed[1].EventHandler() || report.Removed[5].EventHandler() {
+		t.Fatalf("EventHandler incorrect for %v", report.Removed)
+	}
+
+	// Sanitize returns the same output
+	output, err = p.Sanitize(input)
+	if err != nil || output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+}
@@ -0,0 +1,117 @@
+package sanitize
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"sync"
+)
+
+// ErrSafeHTMLScan is returned by SafeHTML.Scan for database values which are not strings or bytes.
+var ErrSafeHTMLScan = errors.New("sanitize: SafeHTML cannot scan value of this type")
+
+// SafeHTML is html which has been sanitized, so that applications may use the type system to keep it apart
+// from untrusted strings. It is returned by SanitizeSafe and Policy.SanitizeSafe, and may be stored in a database
+// or marshaled to json as a string. Html read back from a database or json is sanitized again, see SetSafeHTMLPolicy.
+// Converting other strings to SafeHTML bypasses sanitizing and should be avoided.
+type SafeHTML string
+
+// The policy used to sanitize SafeHTML read by Scan and UnmarshalJSON, set by SetSafeHTMLPolicy.
+var safeHTMLPolicy struct {
+	sync.RWMutex
+	policy *Policy
+}
+
+// SetSafeHTMLPolicy sets the policy used to sanitize SafeHTML read from a database by Scan or from json
+// by UnmarshalJSON, which by default is the default policy, see SetDefaultPolicy. It should allow
+// everything allowed by the policies used to store SafeHTML, so that stored values are read unchanged.
+// Setting a nil policy restores the default. SetSafeHTMLPolicy is safe for concurrent use.
+func SetSafeHTMLPolicy(p *Policy) {
+	safeHTMLPolicy.Lock()
+	safeHTMLPolicy.policy = p
+	safeHTMLPolicy.Unlock()
+}
+
+// sanitizeRead sanitizes html read from outside the program as SafeHTML.
+func sanitizeRead(s string) (SafeHTML, error) {
+	safeHTMLPolicy.RLock()
+	p := safeHTMLPolicy.policy
+	safeHTMLPolicy.RUnlock()
+	if p == nil {
+		p = DefaultPolicy()
+	}
+	return p.SanitizeSafe(s)
+}
+
+// SanitizeSafe sanitizes html as Sanitize does, returning the result as SafeHTML.
+func SanitizeSafe(s string, opts ...Option) (SafeHTML, error) {
+	return newOptions(opts).policy.SanitizeSafe(s)
+}
+
+// SanitizeSafe sanitizes html as Sanitize does, returning the result as SafeHTML.
+func (p *Policy) SanitizeSafe(s string) (SafeHTML, error) {
+	output, err := p.Sanitize(s)
+	return SafeHTML(output), err
+}
+
+// String returns the html.
+func (h SafeHTML) String() string {
+	return string(h)
+}
+
+// HTML returns the html for use in html/template without escaping.
+func (h SafeHTML) HTML() template.HTML {
+	return template.HTML(h)
+}
+
+// Value implements driver.Valuer, storing the html as a string.
+func (h SafeHTML) Value() (driver.Value, error) {
+	return string(h), nil
+}
+
+// Scan implements sql.Scanner, reading html stored as a string or bytes. The html is sanitized again
+// by the policy set with SetSafeHTMLPolicy, as the database cannot guarantee it was stored as SafeHTML.
+// A NULL value gives an empty string.
+func (h *SafeHTML) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return ErrSafeHTMLScan
+	}
+
+	output, err := sanitizeRead(s)
+	if err != nil {
+		return err
+	}
+	*h = output
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing the html as a json string with <, > and & escaped.
+func (h SafeHTML) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(h))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, reading html from a json string. The html is sanitized
+// by the policy set with SetSafeHTMLPolicy, as json may come from anywhere. A json null leaves h unchanged.
+func (h *SafeHTML) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	output, err := sanitizeRead(s)
+	if err != nil {
+		return err
+	}
+	*h = output
+	return nil
+}
@@ -0,0 +1,90 @@
+package sanitize
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSafeHTML(t *testing.T) {
+	input := `<p onclick="alert(1)">Hello <script>x</script><b>world</b></p>`
+	expected := SafeHTML(`<p>Hello <b>world</b></p>`)
+	output, err := SanitizeSafe(input)
+	if err != nil || output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+	output, err = MediaPolicy().SanitizeSafe(input)
+	if err != nil || output != `Hello world` {
+		t.Fatalf(Format, input, `Hello world`, output)
+	}
+
+	// Values are stored as strings, and sanitized again when scanned
+	value, err := expected.Value()
+	if err != nil || value != string(expected) {
+		t.Fatalf(Format, expected, expected, value)
+	}
+	tests := []struct {
+		src      interface{}
+		expected SafeHTML
+	}{
+		{string(expected), expected},
+		{[]byte(input), expected},
+		{nil, ""},
+	}
+	for _, test := range tests {
+		var scanned SafeHTML
+		if err := scanned.Scan(test.src); err != nil || scanned != test.expected {
+			t.Fatalf(Format, test.src, test.expected, scanned)
+		}
+	}
+	var scanned SafeHTML
+	if err := scanned.Scan(1); err != ErrSafeHTMLScan {
+		t.Fatalf("SafeHTML scanned an int: %v", err)
+	}
+
+	// Json marshaling escapes html characters in the string
+	data, err := json.Marshal(struct{ Body SafeHTML }{expected})
+	if err != nil || string(data) != `{"Body":"\u003cp\u003eHello \u003cb\u003eworld\u003c/b\u003e\u003c/p\u003e"}` {
+		t.Fatalf(Format, expected, "json", string(data))
+	}
+
+	// Json is sanitized when unmarshaled
+	var body struct{ Body SafeHTML }
+	if err := json.Unmarshal(data, &body); err != nil || body.Body != expected {
+		t.Fatalf(Format, data, expected, body.Body)
+	}
+	data = []byte(`{"Body":"<script>alert(1)</script><p onclick=\"x()\">a</p>"}`)
+	if err := json.Unmarshal(data, &body); err != nil || body.Body != `<p>a</p>` {
+		t.Fatalf(Format, data, `<p>a</p>`, body.Body)
+	}
+}
+
+func TestSetSafeHTMLPolicy(t *testing.T) {
+	defer SetSafeHTMLPolicy(nil)
+
+	// Html stored using a policy round trips unchanged when read using the same policy
+	input := `<p style="color: red" class="x">a</p><table><tr><td>b</td></tr></table><script>c</script>`
+	stored, err := SanitizeSafe(input, WithPolicy(EmailPolicy()))
+	if err != nil {
+		t.Fatalf("SanitizeSafe failed: %v", err)
+	}
+	SetSafeHTMLPolicy(EmailPolicy())
+	value, _ := stored.Value()
+	var scanned SafeHTML
+	if err := scanned.Scan(value); err != nil || scanned != stored {
+		t.Fatalf(Format, value, stored, scanned)
+	}
+	data, _ := json.Marshal(stored)
+	var unmarshaled SafeHTML
+	if err := json.Unmarshal(data, &unmarshaled); err != nil || unmarshaled != stored {
+		t.Fatalf(Format, data, stored, unmarshaled)
+	}
+
+	// Html which was not stored as SafeHTML is still sanitized
+	if err := scanned.Scan(input); err != nil || scanned != stored {
+		t.Fatalf(Format, input, stored, scanned)
+	}
+	data, _ = json.Marshal(input)
+	if err := json.Unmarshal(data, &unmarshaled); err != nil || unmarshaled != stored {
+		t.Fatalf(Format, data, stored, unmarshaled)
+	}
+}
@@ -5,101 +5,119 @@ import (
 	"bytes"
 	"html"
 	"html/template"
-	"io"
-	"path"
 	"regexp"
 	"strings"
-
-	parser "golang.org/x/net/html"
 )
 
 var (
 	ignoreTags = []string{"title", "script", "style", "iframe", "frame", "frameset", "noframes", "noembed", "embed", "applet", "object", "base"}
 
-	defaultTags = []string{"h1", "h2", "h3", "h4", "h5", "h6", "div", "span", "hr", "p", "br", "b", "i", "strong", "em", "ol", "ul", "li", "a", "img", "pre", "code", "blockquote", "article", "section"}
+	defaultTags = []string{"h1", "h2", "h3", "h4", "h5", "h6", "div", "span", "hr", "p", "br", "b", "i", "strong", "em", "ol", "ul", "li", "a", "img", "pre", "code", "blockquote", "article", "section",
+		"figure", "figcaption", "abbr", "mark", "time", "dl", "dt", "dd"}
+
+	defaultAttributes = []string{"id", "class", "src", "href", "title", "alt", "name", "rel", "cite", "datetime", "lang", "dir"}
 
-	defaultAttributes = []string{"id", "class", "src", "href", "title", "alt", "name", "rel"}
+	// The tags and attributes allowed by HTMLAllowing in earlier versions, which it allows unless SetDefaultPolicy is used
+	legacyTags       = []string{"h1", "h2", "h3", "h4", "h5", "h6", "div", "span", "hr", "p", "br", "b", "i", "strong", "em", "ol", "ul", "li", "a", "img", "pre", "code", "blockquote", "article", "section"}
+	legacyAttributes = []string{"id", "class", "src", "href", "title", "alt", "name", "rel"}
 )
 
 // HTMLAllowing sanitizes html, allowing some tags.
-// Arrays of allowed tags and allowed attributes may optionally be passed as the second and third arguments.
+// Arrays of allowed tags and allowed attributes may optionally be passed as the second and third arguments,
+// otherwise those allowed by earlier versions are used, or those of the default policy if set by SetDefaultPolicy.
+//
+// Deprecated: HTMLAllowing is kept for existing callers and gives the same output as earlier versions,
+// except that urls are checked and normalised by URL, and nested elements such as object are removed
+// with all of their content. Use Sanitize instead for other options and the tags and attributes allowed by default.
 func HTMLAllowing(s string, args ...[]string) (string, error) {
 
-	allowedTags := defaultTags
+	opts := []Option{WithPolicy(legacyPolicy())}
 	if len(args) > 0 {
-		allowedTags = args[0]
+		opts = append(opts, WithTags(args[0]...))
 	}
-	allowedAttributes := defaultAttributes
 	if len(args) > 1 {
-		allowedAttributes = args[1]
+		opts = append(opts, WithAttributes(args[1]...))
 	}
 
-	// Parse the html
-	tokenizer := parser.NewTokenizer(strings.NewReader(s))
-
-	buffer := bytes.NewBufferString("")
-	ignore := ""
-
-	for {
-		tokenType := tokenizer.Next()
-		token := tokenizer.Token()
+	return Sanitize(s, opts...)
+}
 
-		switch tokenType {
+// Entities for curly quotes and non-breaking spaces, which HTML converts to ascii with Typography as earlier versions did.
+// Other typography, including curly quotes written as characters, is left unchanged,
+// Typography may be used with HTMLOptions.Text to convert it.
+var htmlTypographyEntities = regexp.MustCompile(`&#8216;|&#8217;|&#8220;|&#8221;|&nbsp;`)
 
-		case parser.ErrorToken:
-			err := tokenizer.Err()
-			if err == io.EOF {
-				return buffer.String(), nil
-			}
-			return "", err
+// Escaping selects how the plain text returned by HTMLText is escaped.
+type Escaping int
 
-		case parser.StartTagToken:
+// Supported escaping policies.
+const (
+	// EscapeDefault leaves the usual behaviour of HTML unchanged, <, > and & are escaped
+	// but quotes and an & followed by a space are left as they are.
+	EscapeDefault Escaping = iota
 
-			if len(ignore) == 0 && includes(allowedTags, token.Data) {
-				token.Attr = cleanAttributes(token.Attr, allowedAttributes)
-				buffer.WriteString(token.String())
-			} else if includes(ignoreTags, token.Data) {
-				ignore = token.Data
-			}
+	// EscapeNone returns raw plain text with no entities, which must be escaped by the caller before use in html.
+	EscapeNone
 
-		case parser.SelfClosingTagToken:
+	// EscapeMinimal escapes only <, > and &, suitable for text content but not attribute values.
+	EscapeMinimal
 
-			if len(ignore) == 0 && includes(allowedTags, token.Data) {
-				token.Attr = cleanAttributes(token.Attr, allowedAttributes)
-				buffer.WriteString(token.String())
-			} else if token.Data == ignore {
-				ignore = ""
-			}
+	// EscapeFull escapes <, >, &, ' and " as HTMLEscapeString does, suitable for text content or quoted attribute values.
+	EscapeFull
+)
 
-		case parser.EndTagToken:
-			if len(ignore) == 0 && includes(allowedTags, token.Data) {
-				token.Attr = []parser.Attribute{}
-				buffer.WriteString(token.String())
-			} else if token.Data == ignore {
-				ignore = ""
-			}
+// HTMLOptions configures HTMLText.
+type HTMLOptions struct {
+	// Normalization is applied to the text before tags are removed, by default none.
+	Normalization NormalizationForm
 
-		case parser.TextToken:
-			// We allow text content through, unless ignoring this entire tag and its contents (including other tags)
-			if ignore == "" {
-				buffer.WriteString(token.String())
-			}
-		case parser.CommentToken:
-			// We ignore comments by default
-		case parser.DoctypeToken:
-			// We ignore doctypes by default - html5 does not require them and this is intended for sanitizing snippets of text
-		default:
-			// We ignore unknown token types by default
+	// ReplaceInvalid replaces NUL bytes and invalid utf-8 with U+FFFD, by default they are removed.
+	ReplaceInvalid bool
 
-		}
+	// NormalizeInput removes a byte order mark and replaces \r\n and \r line endings with \n before tags are removed.
+	NormalizeInput bool
 
-	}
+	// Text transforms the plain text after tags are removed and entities decoded, before it is escaped,
+	// for example CollapseWhitespace or the Replace method of a WordFilter.
+	Text func(string) string
 
+	// Escaping selects how the result is escaped, by default as HTML does.
+	Escaping Escaping
 }
 
 // HTML strips html tags, replace common entities, and escapes <>&;'" in the result.
 // Note the returned text may contain entities as it is escaped by HTMLEscapeString, and most entities are not translated.
-func HTML(s string) (output string) {
+func HTML(s string) string {
+	return HTMLText(s, HTMLOptions{})
+}
+
+// Text strips html tags in the same way as HTML, and returns plain text which contains no entities at all,
+// for use outside html such as in json, push notifications or plain text emails.
+// Entities are decoded until none remain, so double escaped input such as &amp;#39; gives ' rather than &#39;.
+// The result is not escaped and must be escaped again before use in html.
+func Text(s string) string {
+	return HTMLText(s, HTMLOptions{Escaping: EscapeNone, Text: unescapeEntities})
+}
+
+// unescapeEntities decodes entities in s repeatedly, until decoding leaves it unchanged.
+func unescapeEntities(s string) string {
+	for strings.Contains(s, "&") {
+		unescaped := html.UnescapeString(s)
+		if unescaped == s {
+			break
+		}
+		s = unescaped
+	}
+	return s
+}
+
+// HTMLText strips html tags in the same way as HTML, with additional rules set by opts.
+func HTMLText(s string, opts HTMLOptions) (output string) {
+
+	if opts.NormalizeInput {
+		s = normalizeInput(s)
+	}
+	s = Normalize(cleanUTF8(s, opts.ReplaceInvalid), opts.Normalization)
 
 	// Shortcut strings with no tags in them
 	if !strings.ContainsAny(s, "<>") {
@@ -135,65 +153,62 @@ func HTML(s string) (output string) {
 		output = b.String()
 	}
 
-	// Remove a few common harmless entities, to arrive at something more like plain text
-	output = strings.Replace(output, "&#8216;", "'", -1)
-	output = strings.Replace(output, "&#8217;", "'", -1)
-	output = strings.Replace(output, "&#8220;", "\"", -1)
-	output = strings.Replace(output, "&#8221;", "\"", -1)
-	output = strings.Replace(output, "&nbsp;", " ", -1)
-	output = strings.Replace(output, "&quot;", "\"", -1)
-	output = strings.Replace(output, "&apos;", "'", -1)
+	// Replace entities for curly quotes and non-breaking spaces, to arrive at something more like plain text
+	output = htmlTypographyEntities.ReplaceAllStringFunc(output, func(entity string) string {
+		return Typography(html.UnescapeString(entity), TypographyOptions{KeepDashes: true, KeepEllipses: true})
+	})
 
 	// Translate some entities into their plain text equivalent (for example accents, if encoded as entities)
 	output = html.UnescapeString(output)
 
-	// In case we have missed any tags above, escape the text - removes <, >, &, ' and ".
-	output = template.HTMLEscapeString(output)
-
-	// After processing, remove some harmless entities &, ' and " which are encoded by HTMLEscapeString
-	output = strings.Replace(output, "&#34;", "\"", -1)
-	output = strings.Replace(output, "&#39;", "'", -1)
-	output = strings.Replace(output, "&amp; ", "& ", -1)     // NB space after
-	output = strings.Replace(output, "&amp;amp; ", "& ", -1) // NB space after
+	// Apply any final transform to the plain text, before it is escaped
+	if opts.Text != nil {
+		output = opts.Text(output)
+	}
 
-	return output
+	// In case we have missed any tags above, escape the text
+	return escapeText(output, opts.Escaping)
 }
 
-// We are very restrictive as this is intended for ascii url slugs
-var illegalPath = regexp.MustCompile(`[^[:alnum:]\~\-\./]`)
+// Escapes the characters in text content, leaving quotes as they are
+var minimalEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escapeText escapes plain text for output using the escaping policy given.
+func escapeText(s string, escaping Escaping) string {
+	switch escaping {
+	case EscapeNone:
+		return s
+	case EscapeMinimal:
+		return minimalEscaper.Replace(s)
+	case EscapeFull:
+		return template.HTMLEscapeString(s)
+	}
+
+	// Escape <, >, &, ' and ", then remove some harmless entities &, ' and " which are encoded by HTMLEscapeString
+	s = template.HTMLEscapeString(s)
+	s = strings.Replace(s, "&#34;", "\"", -1)
+	s = strings.Replace(s, "&#39;", "'", -1)
+	s = strings.Replace(s, "&amp; ", "& ", -1)     // NB space after
+	s = strings.Replace(s, "&amp;amp; ", "& ", -1) // NB space after
+	return s
+}
 
 // Path makes a string safe to use as a URL path,
 // removing accents and replacing separators with -.
 // The path may still start at / and is not intended
 // for use as a file system path without prefix.
-func Path(s string) string {
-	// Start with lowercase string
-	filePath := strings.ToLower(s)
-	filePath = strings.Replace(filePath, "..", "", -1)
-	filePath = path.Clean(filePath)
-
-	// Remove illegal characters for paths, flattening accents
-	// and replacing some common separators with -
-	filePath = cleanString(filePath, illegalPath)
-
-	// NB this may be of length 0, caller must check
-	return filePath
+// Options such as WithMaxLength or WithSeparator may be given, see Slug for other rules.
+func Path(s string, opts ...Option) string {
+	return Slug(s, newOptions(opts).slug)
 }
 
 // Remove all other unrecognised characters apart from
 var illegalName = regexp.MustCompile(`[^[:alnum:]-.]`)
 
 // Name makes a string safe to use in a file name by first finding the path basename, then replacing non-ascii characters.
-func Name(s string) string {
-	// Start with lowercase string
-	fileName := strings.ToLower(s)
-	fileName = path.Clean(path.Base(fileName))
-
-	// Remove illegal characters for names, replacing some common separators with -
-	fileName = cleanString(fileName, illegalName)
-
-	// NB this may be of length 0, caller must check
-	return fileName
+// Options such as WithMaxLength or WithSeparator may be given, see FileName for other rules.
+func Name(s string, opts ...Option) string {
+	return FileName(s, newOptions(opts).slug)
 }
 
 // Replace these separators with -
@@ -213,91 +228,67 @@ func BaseName(s string) string {
 	return baseName
 }
 
-// A very limited list of transliterations to catch common european names translated to urls.
-// This set could be expanded with at least caps and many more characters.
-var transliterations = map[rune]string{
-	'À': "A",
-	'Á': "A",
-	'Â': "A",
-	'Ã': "A",
+// Transliterations for letters which do not decompose to ascii, or where the conventional
+// transliteration differs from the base letter (for example ö is written oe in urls).
+// Other accented letters are handled by decomposition in Accents.
+// Capitals listed here keep the transliteration used by earlier versions, other capitals are added
+// with the first letter of the transliteration capitalised.
+var transliterations = withCapitals(map[rune]string{
 	'Ä': "A",
 	'Å': "AA",
 	'Æ': "AE",
-	'Ç': "C",
-	'È': "E",
-	'É': "E",
-	'Ê': "E",
-	'Ë': "E",
-	'Ì': "I",
-	'Í': "I",
-	'Î': "I",
-	'Ï': "I",
 	'Ð': "D",
 	'Ł': "L",
-	'Ñ': "N",
-	'Ò': "O",
-	'Ó': "O",
-	'Ô': "O",
-	'Õ': "O",
 	'Ö': "OE",
 	'Ø': "OE",
 	'Œ': "OE",
-	'Ù': "U",
-	'Ú': "U",
 	'Ü': "UE",
-	'Û': "U",
-	'Ý': "Y",
 	'Þ': "TH",
-	'ẞ': "SS",
-	'à': "a",
-	'á': "a",
-	'â': "a",
-	'ã': "a",
 	'ä': "ae",
 	'å': "aa",
 	'æ': "ae",
-	'ç': "c",
-	'è': "e",
-	'é': "e",
-	'ê': "e",
-	'ë': "e",
-	'ì': "i",
-	'í': "i",
-	'î': "i",
-	'ï': "i",
 	'ð': "d",
 	'ł': "l",
-	'ñ': "n",
-	'ń': "n",
-	'ò': "o",
-	'ó': "o",
-	'ô': "o",
-	'õ': "o",
-	'ō': "o",
 	'ö': "oe",
 	'ø': "oe",
 	'œ': "oe",
-	'ś': "s",
-	'ù': "u",
-	'ú': "u",
-	'û': "u",
-	'ū': "u",
 	'ü': "ue",
-	'ý': "y",
-	'ÿ': "y",
-	'ż': "z",
 	'þ': "th",
 	'ß': "ss",
-}
+	'đ': "d",
+	'ħ': "h",
+	'ı': "i",
+	'ĳ': "ij",
+	'ŀ': "l",
+	'ŋ': "ng",
+	'ŧ': "t",
+	'ſ': "s",
+	'ẞ': "SS",
+})
 
-// Accents replaces a set of accented characters with ascii equivalents.
+// Accents replaces accented characters with ascii equivalents, using any registered transliterators,
+// the transliterations above, or by removing combining marks from the decomposed letter.
+// Letters in cyrillic, greek, arabic and hebrew scripts are also transliterated,
+// cyrillic letters using the CyrillicPassport scheme.
 func Accents(s string) string {
+	return accents(s)
+}
+
+// accents replaces accented characters as Accents does, consulting profiles before the registered and built in transliterations.
+func accents(s string, profiles ...Transliterator) string {
+	// Letters in some scripts are transliterated in pairs
+	s = greekDigraphsReplace(s)
+
 	// Replace some common accent characters
 	b := bytes.NewBufferString("")
 	for _, c := range s {
-		// Check transliterations first
-		if val, ok := transliterations[c]; ok {
+		// Check profiles, registered and built in transliterations first, then try stripping marks from the decomposed letter
+		if val, ok := transliterate(c, profiles); ok {
+			b.WriteString(flatten(val))
+		} else if val, ok := decompose(c); ok {
 			b.WriteString(val)
+		} else if val, ok := transliterateBase(c, profiles); ok {
+			b.WriteString(flatten(val))
 		} else {
 			b.WriteRune(c)
 		}
@@ -313,68 +304,94 @@ var (
 
 	// We are far more restrictive with href attributes.
 	legalHrefAttr = regexp.MustCompile(`\A[/#][^/\\]?|mailto:|http://|https://`)
-)
 
-// cleanAttributes returns an array of attributes after removing malicious ones.
-func cleanAttributes(a []parser.Attribute, allowed []string) []parser.Attribute {
-	if len(a) == 0 {
-		return a
-	}
-
-	var cleaned []parser.Attribute
-	for _, attr := range a {
-		if includes(allowed, attr.Key) {
-
-			val := strings.ToLower(attr.Val)
-
-			// Check for illegal attribute values
-			if illegalAttr.FindString(val) != "" {
-				attr.Val = ""
-			}
+	// Options used to check and normalise href attributes - links to spoofed hosts are removed.
+	hrefOptions = URLOptions{AllowRelative: true, RejectConfusable: true}
 
-			// Check for legal href values - / mailto:// http:// or https://
-			if attr.Key == "href" {
-				if legalHrefAttr.FindString(val) == "" {
-					attr.Val = ""
-				}
-			}
+	// Attributes which hold urls, always checked and normalised as urls.
+	urlAttributes = []string{"href", "cite", "action", "formaction", "poster", "background", "longdesc", "xlink:href", "itemid"}
 
-			// If we still have an attribute, append it to the array
-			if attr.Val != "" {
-				cleaned = append(cleaned, attr)
-			}
-		}
-	}
-	return cleaned
-}
+	// Dates, times and durations allowed in datetime attributes, such as 2006-01-02T15:04:05Z or PT2H
+	legalDatetime = regexp.MustCompile(`\A(\d{4}(-\d{2}(-\d{2})?)?([T ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?)?|\d{2}:\d{2}(:\d{2}(\.\d+)?)?|\d{4}-W\d{2}|P(\d+[YMWD])*(T(\d+[HM])*(\d+(\.\d+)?S)?)?)\z`)
+)
 
 // A list of characters we consider separators in normal strings and replace with our canonical separator - rather than removing.
-var (
-	separators = regexp.MustCompile(`[ &_=+:]`)
-
-	dashes = regexp.MustCompile(`[\-]+`)
-)
+var separators = regexp.MustCompile(`[ &_=+:]`)
 
 // cleanString replaces separators with - and removes characters listed in the regexp provided from string.
 // Accents, spaces, and all characters not in A-Za-z0-9 are replaced.
 func cleanString(s string, r *regexp.Regexp) string {
+	return cleanStringOptions(s, r, SlugOptions{})
+}
+
+// cleanStringOptions is like cleanString, but applies the transliteration and replacement rules set in opts.
+func cleanStringOptions(s string, r *regexp.Regexp, opts SlugOptions) string {
+	replacement := replacementString(opts.Replacement, r)
+	separator := replacementString(opts.Separator, r)
+	if separator == "" {
+		separator = "-"
+	}
 
 	// Remove any trailing space to avoid ending on -
 	s = strings.Trim(s, " ")
 
+	// Replace emoji with their names if required
+	if opts.Emoji == EmojiAlias {
+		s = replaceEmoji(s, emojiWords)
+	}
+
 	// Flatten accents first so that if we remove non-ascii we still get a legible name
-	s = Accents(s)
+	// The passport scheme for cyrillic is built in, so only another scheme is consulted before registered transliterators
+	profiles := []Transliterator{symbolTransliterator(opts.Symbols), langProfile(opts.Lang)}
+	if opts.Cyrillic != CyrillicPassport {
+		profiles = append(profiles, cyrillicTable(opts.Cyrillic))
+	}
+	if opts.CJK == CJKTransliterate {
+		s = romanizeKana(s)
+		profiles = append(profiles, opts.CJKTransliterator, hangulRomanizer{})
+	}
+	s = accents(s, profiles...)
 
-	// Replace certain joining characters with a dash
-	s = separators.ReplaceAllString(s, "-")
+	// Replace certain joining characters with a dash, or the separator if set
+	s = separators.ReplaceAllString(s, separator)
 
 	// Remove all other unrecognised characters - NB we do allow any printable characters
-	s = r.ReplaceAllString(s, "")
+	if opts.CJK == CJKPreserve || opts.Emoji == EmojiKeep {
+		s = r.ReplaceAllStringFunc(s, preserve(opts, replacement))
+	} else {
+		s = r.ReplaceAllString(s, replacement)
+	}
 
-	// Remove any multiple dashes caused by replacements above
-	s = dashes.ReplaceAllString(s, "-")
+	// Remove any runs of dashes caused by replacements above, and dashes at either end
+	return collapseSeparators(s, separator+"-"+replacement)
+}
 
-	return s
+// collapseSeparators replaces each run of the characters in seps with the first character of the run,
+// and trims them from both ends of each path segment and from either side of a dot.
+func collapseSeparators(s, seps string) string {
+	segments := strings.Split(s, "/")
+	for i, segment := range segments {
+		b := bytes.NewBufferString("")
+		inRun := false
+		for _, r := range segment {
+			if strings.ContainsRune(seps, r) {
+				if !inRun {
+					b.WriteRune(r)
+				}
+				inRun = true
+			} else {
+				b.WriteRune(r)
+				inRun = false
+			}
+		}
+		segment = b.String()
+		for _, sep := range seps {
+			segment = strings.Replace(segment, string(sep)+".", ".", -1)
+			segment = strings.Replace(segment, "."+string(sep), ".", -1)
+		}
+		segments[i] = strings.Trim(segment, seps)
+	}
+	return strings.Join(segments, "/")
 }
 
 // includes checks for inclusion of a string in a []string.
@@ -22,6 +22,9 @@ var urls = []Test{
 	{"../4 icon.*", `/4-icon.`},
 	{"Spac ey/Nôm/test før url", `spac-ey/nom/test-foer-url`},
 	{"../*", `/`},
+	{"a - - b", `a-b`},
+	{"/-news-/ _ = + : mixed & separators ?/", `/news/mixed-separators`},
+	{"/files/. ./. ./etc/passwd", `/files/etc/passwd`},
 }
 
 func TestPath(t *testing.T) {
@@ -54,6 +57,8 @@ var fileNames = []Test{
 	{"../4 icon-testé *8%^\"'\".jpg ", `4-icon-teste-8.jpg`},
 	{"Überfluß an Döner macht schöner.JPEG", `ueberfluss-an-doener-macht-schoener.jpeg`},
 	{"Ä-_-Ü_:()_Ö-_-ä-_-ü-_-ö-_ß.webm", `ae-ue-oe-ae-ue-oe-ss.webm`},
+	{"_-_draft_-_.txt", `draft.txt`},
+	{". .", ``},
 }
 
 func TestName(t *testing.T) {
@@ -78,10 +83,30 @@ func BenchmarkName(b *testing.B) {
 
 var baseFileNames = []Test{
 	{"The power & the Glory jpg file. The end", `The-power-the-Glory-jpg-file-The-end`},
-	{"/../../4-iCoN.jpg", `-4-iCoN-jpg`},
+	{"/../../4-iCoN.jpg", `4-iCoN-jpg`},
 	{"And/Or", `And-Or`},
 	{"Sonic.EXE", `Sonic-EXE`},
 	{"012: #Fetch for Defaults", `012-Fetch-for-Defaults`},
+	{"  - _Leading and trailing_ -  ", `Leading-and-trailing`},
+}
+
+var accentTests = []Test{
+	{"Überfluß an Döner", `UEberfluss an Doener`},
+	{"Čeština, Šťastný, Žluťoučký", `Cestina, Stastny, Zlutoucky`},
+	{"Wałęsa, Łódź, Kraków", `Walesa, Lodz, Krakow`},
+	{"Erdoğan, İstanbul, Şişli", `Erdogan, Istanbul, Sisli`},
+	{"Győr, Ștefan, Đorđe", `Gyor, Stefan, Dorde`},
+	{"Ħamrun, ŧest", `Hamrun, test`},
+	{"Москва, 서울, 東京", `Moskva, 서울, 東京`},
+}
+
+func TestAccents(t *testing.T) {
+	for _, test := range accentTests {
+		output := Accents(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
 }
 
 func TestBaseName(t *testing.T) {
@@ -126,6 +151,8 @@ var htmlTests = []Test{
 #0000108&#0000101&#0000114&#0000116&#0000040&#0000039&#0000088&#0000083&#0000083&#0000039&#0000041>`, ``},
 	{`'';!--"<XSS>=&{()}`, `'';!--"=&amp;{()}`},
 	{"LINE 1<br />\nLINE 2", "LINE 1\nLINE 2"},
+	{"<p>&#8216;Curly&#8217; &#8220;quotes&#8221;&nbsp;and&#160;spaces</p>", "'Curly' \"quotes\" and\u00a0spaces\n"},
+	{"<p>“Curly” quotes&nbsp;and spaces — kept…</p>", "“Curly” quotes and spaces — kept…\n"},
 
 	// Examples from https://githubengineering.com/githubs-post-csp-journey/
 	{`<img src='https://example.com/log_csrf?html=`, ``},
@@ -142,6 +169,7 @@ var htmlTests = []Test{
 <form action="/logout">
   <input name="authenticity_token" type="hidden" value="secret1">
 </form>`, `Click --  `},
+	{"<p>«Bonjour» ‹a› 5′11″</p>\u2003x", "«Bonjour» ‹a› 5′11″\n\u2003x"},
 }
 
 func TestHTML(t *testing.T) {
@@ -206,6 +234,7 @@ var htmlTestsAllowing = []Test{
 	{`<IMG SRC=&#0000106&#0000097&#0000118&#0000097&#0000115&#0000099&#0000114&#0000105&#0000112&#0000116&#0000058&#0000097&
 #0000108&#0000101&#0000114&#0000116&#0000040&#0000039&#0000088&#0000083&#0000083&#0000039&#0000041>`, `<img>`},
 	{`<a href="mailto:cool@test.com?subject=cooool">cool guy</a>`, `<a href="mailto:cool@test.com?subject=cooool">cool guy</a>`},
+	{`<a href="https://аpple.com/login">apple</a>`, `<a>apple</a>`},
 }
 
 func TestHTMLAllowed(t *testing.T) {
@@ -234,3 +263,55 @@ func BenchmarkHTMLAllowed(b *testing.B) {
 		}
 	}
 }
+
+var semanticTests = []Test{
+	{`<figure><img src="/a.png" alt="A"><figcaption>Caption</figcaption></figure>`, `<figure><img src="/a.png" alt="A"><figcaption>Caption</figcaption></figure>`},
+	{`<blockquote cite="https://Example.com/source">quote</blockquote>`, `<blockquote cite="https://example.com/source">quote</blockquote>`},
+	{`<blockquote cite="javascript:alert(1)">quote</blockquote>`, `<blockquote>quote</blockquote>`},
+	{`<time datetime="2024-03-01T10:30:00Z">1 March</time> <time datetime="PT2H30M">long</time>`, `<time datetime="2024-03-01T10:30:00Z">1 March</time> <time datetime="PT2H30M">long</time>`},
+	{`<time datetime="tomorrow">soon</time>`, `<time>soon</time>`},
+	{`<abbr title="HyperText Markup Language">HTML</abbr> is <mark>marked</mark>`, `<abbr title="HyperText Markup Language">HTML</abbr> is <mark>marked</mark>`},
+	{`<dl><dt>Term</dt><dd>Definition</dd></dl>`, `<dl><dt>Term</dt><dd>Definition</dd></dl>`},
+}
+
+func TestSanitizeSemantic(t *testing.T) {
+	for _, test := range semanticTests {
+		output, err := Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var langDirTests = []Test{
+	{`<p lang="en" dir="ltr">English</p>`, `<p lang="en" dir="ltr">English</p>`},
+	{`<span lang="zh-Hant-TW">中文</span><span lang="ar" dir="RTL">عربي</span>`, `<span lang="zh-Hant-TW">中文</span><span lang="ar" dir="RTL">عربي</span>`},
+	{`<p dir="auto">auto</p><p dir="down">bad</p>`, `<p dir="auto">auto</p><p>bad</p>`},
+	{`<p lang="en_US">underscore</p><p lang="x&quot;onclick">quote</p><p lang="notalanguagetag">long</p>`, `<p>underscore</p><p>quote</p><p>long</p>`},
+}
+
+func TestSanitizeLangDir(t *testing.T) {
+	for _, test := range langDirTests {
+		output, err := Sanitize(test.input)
+		if err != nil || output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var textTests = []Test{
+	{`<p>Tom &amp; "Jerry's" <b>cat</b></p>`, "Tom & \"Jerry's\" cat\n"},
+	{`It&#39;s &lt;b&gt;bold&lt;/b&gt;`, `It's <b>bold</b>`},
+	{`Double &amp;#39;escaped&amp;#39; &amp;amp;amp;`, `Double 'escaped' &`},
+	{`&copy; 2024 &eacute;t&eacute;`, `© 2024 été`},
+	{`AT&T & co`, `AT&T & co`},
+}
+
+func TestText(t *testing.T) {
+	for _, test := range textTests {
+		output := Text(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Arguments made only of these characters need no quoting in a posix shell
+var safeShellArg = regexp.MustCompile(`\A[[:alnum:]@%_+=:,./-]+\z`)
+
+// ShellArg quotes a string for use as a single argument in a posix shell command,
+// so that a file name from Name, for example, cannot inject commands.
+// Arguments with characters other than letters, digits and @%_+=:,./- are wrapped in single quotes,
+// with each single quote inside written as a closing quote, an escaped quote and an opening quote.
+// NUL bytes, which cannot be passed as arguments, are removed.
+// NB quoting does not stop an argument starting with - being read as an option, use -- before such arguments.
+func ShellArg(s string) string {
+	s = strings.Replace(s, "\x00", "", -1)
+	if safeShellArg.MatchString(s) {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// ShellArgWindows quotes a string for use as a single argument in a windows command line,
+// following the rules used by CommandLineToArgvW and the microsoft c runtime to split arguments.
+// Arguments containing spaces, tabs or quotes are wrapped in double quotes, quotes are escaped with \
+// and backslashes before a quote are doubled. NUL bytes are removed.
+// NB cmd.exe interprets characters such as & | < > ^ and % itself, run programs directly instead.
+func ShellArgWindows(s string) string {
+	s = strings.Replace(s, "\x00", "", -1)
+	if s != "" && !strings.ContainsAny(s, " \t\n\v\"") {
+		return s
+	}
+
+	b := strings.Builder{}
+	b.WriteByte('"')
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			slashes++
+		case '"':
+			// Double the preceding backslashes and escape the quote
+			b.WriteString(strings.Repeat(`\`, slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		b.WriteByte(s[i])
+	}
+
+	// Backslashes before the closing quote are doubled
+	b.WriteString(strings.Repeat(`\`, slashes))
+	b.WriteByte('"')
+	return b.String()
+}
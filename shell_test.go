@@ -0,0 +1,46 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var shellArgTests = []Test{
+	{"report.pdf", `report.pdf`},
+	{"/var/files/report-2024_v1.pdf", `/var/files/report-2024_v1.pdf`},
+	{"", `''`},
+	{"two words", `'two words'`},
+	{"it's", `'it'\''s'`},
+	{"$(rm -rf ~)", `'$(rm -rf ~)'`},
+	{"a; cat /etc/passwd", `'a; cat /etc/passwd'`},
+	{"`id`", "'`id`'"},
+	{"nul\x00byte", `nulbyte`},
+	{"naïve", `'naïve'`},
+}
+
+func TestShellArg(t *testing.T) {
+	for _, test := range shellArgTests {
+		output := ShellArg(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var shellArgWindowsTests = []Test{
+	{"report.pdf", `report.pdf`},
+	{`C:\Program Files\app`, `"C:\Program Files\app"`},
+	{"", `""`},
+	{`say "hi"`, `"say \"hi\""`},
+	{`trailing\ slash\`, `"trailing\ slash\\"`},
+	{`a\"b`, `"a\\\"b"`},
+	{`C:\path\file.txt`, `C:\path\file.txt`},
+}
+
+func TestShellArgWindows(t *testing.T) {
+	for _, test := range shellArgWindowsTests {
+		output := ShellArgWindows(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package sanitize
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// ReservedSlugs is a list of path segments commonly reserved by web applications,
+// suitable for use as SlugOptions.Reserved.
+var ReservedSlugs = []string{"admin", "api", "assets", "auth", "login", "logout", "register", "signup", "settings", "static", "users"}
+
+// SlugOptions configures Slug and FileName. The zero value produces the same result as Path and Name.
+type SlugOptions struct {
+	// PreserveCase keeps the case of letters instead of lowercasing, for names containing case sensitive ids.
+	PreserveCase bool
+
+	// Replacement replaces each run of removed characters instead of deleting them, so words stay distinguishable.
+	// It must be a character allowed in the output, such as - or _, otherwise - is used.
+	Replacement rune
+
+	// Separator replaces spaces and joining characters such as _ & = + : instead of -.
+	// It must be a character allowed in the output, such as ~, otherwise - is used.
+	Separator rune
+
+	// Lang selects the case rules and transliterations for a language, see AccentsLang.
+	Lang string
+
+	// Cyrillic selects the scheme used to transliterate cyrillic letters.
+	Cyrillic CyrillicScheme
+
+	// Emoji selects how emoji are treated, by default they are removed.
+	Emoji EmojiPolicy
+
+	// Symbols transliterates symbols as separate words instead of removing them, for example SymbolWords or SymbolCodes.
+	Symbols map[rune]string
+
+	// CJK selects how chinese, japanese and korean characters are treated, by default they are removed.
+	CJK CJKStrategy
+
+	// CJKTransliterator provides readings for chinese characters when CJK is CJKTransliterate,
+	// for example pinyin. A trailing space in a reading separates it from the next with -.
+	CJKTransliterator Transliterator
+
+	// Reserved lists path segments which may not be used, for example ReservedSlugs.
+	Reserved []string
+
+	// ReservedSuffix is appended to reserved segments, if empty slugs containing a reserved segment are rejected.
+	// Characters which are not allowed in the slug, and /, are removed from the suffix.
+	ReservedSuffix string
+
+	// Normalization is applied before transliteration. Input is always composed with NFC,
+	// so decomposed text such as macOS file names gives the same result, NFKC also folds compatibility characters.
+	Normalization NormalizationForm
+
+	// MaxLength limits the number of characters in the slug, counting grapheme clusters, if it is greater than zero.
+	// Slugs are shortened at a word boundary where possible, names keep their extension.
+	MaxLength int
+}
+
+// We are very restrictive as this is intended for ascii url slugs
+var illegalPath = regexp.MustCompile(`[^[:alnum:]\~\-\./]`)
+
+// Slug makes a string safe to use as a URL path in the same way as Path,
+// with additional rules set by opts.
+// If the slug is rejected an empty string is returned.
+func Slug(s string, opts SlugOptions) string {
+	// Start with a normalised, lowercase string
+	filePath := lower(normalize(s, opts), opts)
+	filePath = strings.Replace(filePath, "..", "", -1)
+	filePath = path.Clean(filePath)
+
+	// Remove illegal characters for paths, flattening accents
+	// and replacing some common separators with -
+	filePath = cleanStringOptions(filePath, illegalPath, opts)
+
+	// Removing separators may join dots into a parent segment, as in ". ."
+	filePath = removeParentSegments(filePath)
+
+	// Limit the length before checking reserved words, which might be revealed by shortening
+	if opts.MaxLength > 0 {
+		filePath = truncateSlug(filePath, opts.MaxLength, slugSeparators(opts, illegalPath))
+	}
+
+	// Check for reserved words in any segment of the path
+	if len(opts.Reserved) > 0 {
+		filePath = reserveSegments(filePath, opts.Reserved, opts.ReservedSuffix, illegalPath)
+	}
+
+	// NB this may be of length 0, caller must check
+	return filePath
+}
+
+// FileName makes a string safe to use in a file name in the same way as Name,
+// with additional rules set by opts.
+// If the name is rejected an empty string is returned.
+func FileName(s string, opts SlugOptions) string {
+	// Start with a normalised, lowercase string
+	fileName := lower(normalize(s, opts), opts)
+	fileName = path.Clean(path.Base(fileName))
+
+	// Remove illegal characters for names, replacing some common separators with -
+	fileName = cleanStringOptions(fileName, illegalName, opts)
+	fileName = removeParentSegments(fileName)
+
+	// Limit the length of the name, keeping the extension
+	if opts.MaxLength > 0 {
+		ext := path.Ext(fileName)
+		if Length(ext) >= opts.MaxLength {
+			ext = ""
+		}
+		fileName = truncateSlug(strings.TrimSuffix(fileName, ext), opts.MaxLength-Length(ext), slugSeparators(opts, illegalName)) + ext
+	}
+
+	// Check for reserved names
+	if len(opts.Reserved) > 0 {
+		fileName = reserveSegments(fileName, opts.Reserved, opts.ReservedSuffix, illegalName)
+	}
+
+	// NB this may be of length 0, caller must check
+	return fileName
+}
+
+// removeParentSegments removes .. segments from p, which would refer to the parent directory.
+func removeParentSegments(p string) string {
+	segments := strings.Split(p, "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		if segment != ".." {
+			kept = append(kept, segment)
+		}
+	}
+	return strings.Join(kept, "/")
+}
+
+// The number of numbered suffixes UniqueSlug tries before switching to hashed suffixes.
+const maxSlugSuffix = 100
+
+// The number of hashed suffixes UniqueSlug tries before giving up.
+const maxSlugHashes = 10
+
+// UniqueSlug makes a slug from text using Path, then appends -2, -3 and so on
+// until exists returns false. If many numbered slugs already exist, a short hash is used as the suffix instead.
+// If text gives an empty slug, or no unique slug is found, an empty string is returned.
+func UniqueSlug(text string, exists func(string) bool) string {
+	slug, _ := uniqueSlug(Path(text), 2, exists)
+	return slug
+}
+
+// uniqueSlug returns slug if it does not exist, otherwise slug with the first suffix from start which does not exist,
+// and the number of the suffix used. Callers making many slugs may start from the last suffix used,
+// as suffixes before it are known to exist. An empty string is returned if maxSlugHashes hashed suffixes also exist.
+func uniqueSlug(slug string, start int, exists func(string) bool) (string, int) {
+	if slug == "" || !exists(slug) {
+		return slug, 0
+	}
+
+	hashes := 0
+	for i := start; hashes < maxSlugHashes; i++ {
+		var candidate string
+		if i < maxSlugSuffix {
+			candidate = slug + "-" + strconv.Itoa(i)
+		} else {
+			candidate = slug + "-" + slugHash(slug+strconv.Itoa(i))
+			hashes++
+		}
+		if !exists(candidate) {
+			return candidate, i
+		}
+	}
+	return "", 0
+}
+
+// slugHash returns a short hex hash of s.
+func slugHash(s string) string {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// truncateSlug shortens a slug to at most n grapheme clusters, without splitting percent-encoded characters,
+// cutting at the last of seps if the limit falls within a word.
+func truncateSlug(s string, n int, seps string) string {
+	// Preserved characters are percent-encoded, count them as the characters they represent
+	unescaped, err := url.PathUnescape(s)
+	if err != nil || Length(unescaped) <= n {
+		return s
+	}
+
+	end := 0
+	for i := 0; i < n; i++ {
+		end += graphemeLength(unescaped[end:])
+	}
+	truncated := unescaped[:end]
+	if next, _ := utf8.DecodeRuneInString(unescaped[end:]); next != '/' && !strings.ContainsRune(seps, next) {
+		if i := strings.LastIndexAny(truncated, seps+"/"); i > 0 {
+			truncated = truncated[:i]
+		}
+	}
+	truncated = strings.TrimRight(truncated, seps)
+
+	// Encode preserved characters again
+	b := strings.Builder{}
+	for _, r := range truncated {
+		if r > unicode.MaxASCII {
+			b.WriteString(url.PathEscape(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// preserve returns a function for use with ReplaceAllStringFunc which percent-encodes characters kept by opts,
+// and replaces any other match with replacement.
+func preserve(opts SlugOptions, replacement string) func(string) string {
+	return func(s string) string {
+		r, _ := utf8.DecodeRuneInString(s)
+		if (opts.CJK == CJKPreserve && isCJK(r)) || (opts.Emoji == EmojiKeep && (isEmoji(r) || isEmojiComponent(r))) {
+			return url.PathEscape(s)
+		}
+		return replacement
+	}
+}
+
+// emojiWords returns the shortcode for an emoji sequence as separate words, for use in slugs.
+func emojiWords(seq string) string {
+	alias := emojiAlias(seq)
+	if alias == "" {
+		return ""
+	}
+	return " " + alias + " "
+}
+
+// normalize composes s using the normalization form in opts, or NFC if none is set,
+// after removing NUL bytes and invalid utf-8.
+func normalize(s string, opts SlugOptions) string {
+	s = cleanUTF8(s, false)
+	if opts.Normalization == NoNormalization {
+		return Normalize(s, NFC)
+	}
+	return Normalize(s, opts.Normalization)
+}
+
+// lower returns s in lowercase unless opts.PreserveCase is set, following the case rules of opts.Lang if set,
+// so that for example the turkish İ becomes i rather than i followed by a combining dot.
+func lower(s string, opts SlugOptions) string {
+	if opts.PreserveCase {
+		return s
+	}
+	if opts.Lang != "" {
+		tag, err := language.Parse(opts.Lang)
+		if err == nil {
+			return cases.Lower(tag).String(s)
+		}
+	}
+	return strings.ToLower(s)
+}
+
+// slugSeparators returns the characters which may separate words in a slug made with opts.
+func slugSeparators(opts SlugOptions, illegal *regexp.Regexp) string {
+	return "-" + replacementString(opts.Separator, illegal) + replacementString(opts.Replacement, illegal)
+}
+
+// replacementString returns r as a string, or - if r would itself be removed by the illegal regexp.
+func replacementString(r rune, illegal *regexp.Regexp) string {
+	if r == 0 {
+		return ""
+	}
+	if illegal.MatchString(string(r)) {
+		return "-"
+	}
+	return string(r)
+}
+
+// reserveSegments appends suffix to any segment of p in the reserved list,
+// or returns an empty string if a reserved segment is found and suffix is empty.
+// Characters matched by illegal and / are removed from suffix, so it cannot add segments or unsafe characters.
+func reserveSegments(p string, reserved []string, suffix string, illegal *regexp.Regexp) string {
+	suffix = strings.Replace(illegal.ReplaceAllString(suffix, ""), "/", "", -1)
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		if segment == "" || !includesFold(reserved, segment) {
+			continue
+		}
+		if suffix == "" {
+			return ""
+		}
+		segments[i] = segment + suffix
+	}
+	return strings.Join(segments, "/")
+}
+
+// includesFold checks for inclusion of a string in a []string, ignoring case.
+func includesFold(a []string, s string) bool {
+	for _, as := range a {
+		if strings.EqualFold(as, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var slugTests = []struct {
+	input    string
+	opts     SlugOptions
+	expected string
+}{
+	{"Read Me", SlugOptions{}, `read-me`},
+	{"Admin", SlugOptions{Reserved: ReservedSlugs}, ``},
+	{"/blog/Login", SlugOptions{Reserved: ReservedSlugs}, ``},
+	{"Admin", SlugOptions{Reserved: ReservedSlugs, ReservedSuffix: "-page"}, `admin-page`},
+	{"/api/static/doc", SlugOptions{Reserved: ReservedSlugs, ReservedSuffix: "-1"}, `/api-1/static-1/doc`},
+	{"Administrators", SlugOptions{Reserved: ReservedSlugs}, `administrators`},
+	{"about", SlugOptions{Reserved: []string{"About"}}, ``},
+	{"/Videos/dQw4w9WgXcQ Ünïcode", SlugOptions{PreserveCase: true}, `/Videos/dQw4w9WgXcQ-UEnicode`},
+	{"/user/test/I am a long url's_-?ASDF@£$%£%^testé.html", SlugOptions{Replacement: '-'}, `/user/test/i-am-a-long-url-s-asdf-teste.html`},
+	{"C++ & C# (compared)", SlugOptions{Replacement: '~'}, `c-c~compared`},
+	{"a@b", SlugOptions{Replacement: '@'}, `a-b`},
+	{"C++ & C# (compared)", SlugOptions{Separator: '~', Replacement: '~'}, `c~c~compared`},
+	{"Q&A: why", SlugOptions{Separator: '_'}, `q-a-why`},
+	{"İstanbul Işık", SlugOptions{Replacement: '-'}, `istanbul-isik`},
+	{"İstanbul Işık", SlugOptions{Replacement: '-', Lang: "tr"}, `istanbul-isik`},
+	{"DİYARBAKIR", SlugOptions{Replacement: '-', Lang: "tr-TR"}, `diyarbakir`},
+	{"A Very Long Title About Slugs", SlugOptions{MaxLength: 14}, `a-very-long`},
+	{"A Very Long Title About Slugs", SlugOptions{MaxLength: 11}, `a-very-long`},
+	{"Supercalifragilistic", SlugOptions{MaxLength: 5}, `super`},
+	{"日本語のタイトル", SlugOptions{CJK: CJKPreserve, MaxLength: 3}, `%E6%97%A5%E6%9C%AC%E8%AA%9E`},
+	{"A Very Long Title", SlugOptions{Separator: '~', MaxLength: 7}, `a~very`},
+	{"A Very Long Title", SlugOptions{Separator: '~', MaxLength: 11}, `a~very~long`},
+	{"Admin", SlugOptions{Reserved: ReservedSlugs, ReservedSuffix: "/.."}, `admin..`},
+	{"Admin", SlugOptions{Reserved: ReservedSlugs, ReservedSuffix: " x"}, `adminx`},
+	{"Admin", SlugOptions{Reserved: ReservedSlugs, ReservedSuffix: "/"}, ``},
+}
+
+func TestSlug(t *testing.T) {
+	for _, test := range slugTests {
+		output := Slug(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var fileNameTests = []struct {
+	input    string
+	opts     SlugOptions
+	expected string
+}{
+	{"/var/files/Report Final.PDF", SlugOptions{}, `report-final.pdf`},
+	{"/var/files/Report Final.PDF", SlugOptions{PreserveCase: true}, `Report-Final.PDF`},
+	{"token_aZ-09_Xy.txt", SlugOptions{PreserveCase: true}, `token-aZ-09-Xy.txt`},
+	{"CON", SlugOptions{Reserved: []string{"con", "nul"}, ReservedSuffix: "-file"}, `con-file`},
+	{"Q&A: why? how!.txt", SlugOptions{Replacement: '_'}, `q-a-why-how.txt`},
+	{"Annual Report Final Draft.pdf", SlugOptions{MaxLength: 20}, `annual-report.pdf`},
+	{"Annual Report.tar.gz", SlugOptions{MaxLength: 2}, `an`},
+	{"CON", SlugOptions{Reserved: []string{"con"}, ReservedSuffix: "/../x"}, `con..x`},
+}
+
+var lowerTests = []struct {
+	input    string
+	lang     string
+	expected string
+}{
+	{"IŞIK İZMİR", "", `işik izmir`},
+	{"IŞIK İZMİR", "tr", `ışık izmir`},
+	{"IŞIK İZMİR", "az", `ışık izmir`},
+	{"IŞIK İZMİR", "not a tag", `işik izmir`},
+}
+
+func TestLower(t *testing.T) {
+	for _, test := range lowerTests {
+		output := lower(test.input, SlugOptions{Lang: test.lang})
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	for _, test := range fileNameTests {
+		output := FileName(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+// Removing illegal characters may join dots into a .. segment, which must not be kept
+var parentSegmentTests = []Test{
+	{"/files/../../etc/passwd", `/files/etc/passwd`},
+	{"/files/. ./. ./etc/passwd", `/files/etc/passwd`},
+	{"/files/.?./.$./etc/passwd", `/files/etc/passwd`},
+	{". .", ``},
+	{"a/. ./b", `a/b`},
+}
+
+func TestParentSegments(t *testing.T) {
+	for _, test := range parentSegmentTests {
+		output := Path(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+		output = Name(test.input)
+		if output == ".." || strings.Contains(output, "/") {
+			t.Fatalf("Name kept a parent segment for %q: %q", test.input, output)
+		}
+	}
+	if output := removeParentSegments("../a/../b/.."); output != `a/b` {
+		t.Fatalf(Format, "../a/../b/..", `a/b`, output)
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	taken := map[string]bool{"hello-world": true, "hello-world-2": true}
+	exists := func(s string) bool { return taken[s] }
+
+	tests := []Test{
+		{"Hello World", `hello-world-3`},
+		{"Hello Go", `hello-go`},
+		{"@£$", ``},
+	}
+	for _, test := range tests {
+		output := UniqueSlug(test.input, exists)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	// Once numbered slugs are exhausted a hashed suffix is used
+	output := UniqueSlug("Hello World", func(s string) bool { return len(s) < len("hello-world-")+8 })
+	if len(output) != len("hello-world-")+8 {
+		t.Fatalf(Format, "Hello World", "hello-world-xxxxxxxx", output)
+	}
+
+	// If every slug exists an empty string is returned
+	output = UniqueSlug("Hello World", func(string) bool { return true })
+	if output != "" {
+		t.Fatalf(Format, "Hello World", "", output)
+	}
+}
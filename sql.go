@@ -0,0 +1,53 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The maximum length of an identifier, the limit in postgres is 63 bytes and in mysql 64 characters
+const maxSQLIdentifier = 63
+
+// Reserved words common to the SQL standard, postgres, mysql and sqlite, which may not be used as identifiers.
+var sqlReserved = []string{"add", "all", "alter", "and", "any", "as", "asc", "between", "by", "case", "cast", "check", "column", "constraint", "create", "cross", "current_date", "current_time", "current_timestamp", "current_user", "database", "default", "delete", "desc", "distinct", "drop", "else", "end", "except", "exec", "exists", "false", "fetch", "for", "foreign", "from", "full", "grant", "group", "having", "in", "index", "inner", "insert", "intersect", "into", "is", "join", "key", "left", "like", "limit", "not", "null", "offset", "on", "or", "order", "outer", "primary", "references", "revoke", "right", "rowid", "select", "set", "table", "then", "to", "true", "truncate", "union", "unique", "update", "user", "using", "values", "view", "when", "where", "with"}
+
+// Only ascii letters, digits and _ are allowed in identifiers
+var illegalSQLIdentifier = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// SQLIdentifier makes a string safe to use as an unquoted table or column name, for example in a report builder.
+// Accents are transliterated, letters lowercased, and other characters replaced with _.
+// Identifiers starting with a digit are prefixed with _, and identifiers are limited to 63 characters.
+// If the identifier is a reserved word an empty string is returned.
+func SQLIdentifier(s string) string {
+	id := strings.ToLower(Accents(s))
+	id = illegalSQLIdentifier.ReplaceAllString(id, "_")
+	id = strings.Trim(id, "_")
+
+	if id != "" && id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+	if len(id) > maxSQLIdentifier {
+		id = strings.TrimRight(id[:maxSQLIdentifier], "_")
+	}
+
+	if includes(sqlReserved, id) {
+		return ""
+	}
+
+	return id
+}
+
+// SQLLikeEscape escapes the wildcards % and _ and the escape character itself in s,
+// so that user input matches literally in a LIKE pattern. The query must declare the same
+// escape character, for example LIKE ? ESCAPE '\'.
+func SQLLikeEscape(s string, escapeChar rune) string {
+	escape := string(escapeChar)
+	b := strings.Builder{}
+	for _, r := range s {
+		if r == '%' || r == '_' || r == escapeChar {
+			b.WriteString(escape)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
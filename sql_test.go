@@ -0,0 +1,49 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var sqlIdentifierTests = []Test{
+	{"users", `users`},
+	{"First Name", `first_name`},
+	{"Straße", `strasse`},
+	{"total; DROP TABLE users--", `total_drop_table_users`},
+	{"`id`", `id`},
+	{"2020 sales", `_2020_sales`},
+	{"Select", ``},
+	{"selection", `selection`},
+	{"!!!", ``},
+	{strings.Repeat("long_", 20), strings.Repeat("long_", 12) + "lon"},
+}
+
+func TestSQLIdentifier(t *testing.T) {
+	for _, test := range sqlIdentifierTests {
+		output := SQLIdentifier(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var sqlLikeTests = []struct {
+	input    string
+	escape   rune
+	expected string
+}{
+	{"plain", '\\', `plain`},
+	{"100%", '\\', `100\%`},
+	{"snake_case", '\\', `snake\_case`},
+	{`back\slash`, '\\', `back\\slash`},
+	{"a!b%c_d", '!', `a!!b!%c!_d`},
+}
+
+func TestSQLLikeEscape(t *testing.T) {
+	for _, test := range sqlLikeTests {
+		output := SQLLikeEscape(test.input, test.escape)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
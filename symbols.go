@@ -0,0 +1,55 @@
+package sanitize
+
+// SymbolWords transliterates common symbols to english words, for use as SlugOptions.Symbols.
+var SymbolWords = TransliterationMap{
+	'&': "and",
+	'@': "at",
+	'+': "plus",
+	'%': "percent",
+	'°': "degrees",
+	'©': "c",
+	'®': "r",
+	'™': "tm",
+	'$': "dollar",
+	'¢': "cent",
+	'€': "euro",
+	'£': "pound",
+	'¥': "yen",
+	'₹': "rupee",
+	'₽': "ruble",
+	'₩': "won",
+	'₿': "bitcoin",
+}
+
+// SymbolCodes transliterates common symbols to short codes, with currencies as ISO 4217 codes,
+// for use as SlugOptions.Symbols.
+var SymbolCodes = TransliterationMap{
+	'&': "and",
+	'@': "at",
+	'+': "plus",
+	'%': "pct",
+	'°': "deg",
+	'©': "c",
+	'®': "r",
+	'™': "tm",
+	'$': "usd",
+	'€': "eur",
+	'£': "gbp",
+	'¥': "jpy",
+	'₹': "inr",
+	'₽': "rub",
+	'₩': "krw",
+	'₿': "btc",
+}
+
+// symbolTransliterator transliterates symbols as separate words, so that 100% becomes 100-percent.
+type symbolTransliterator map[rune]string
+
+// Transliterate returns the transliteration of r surrounded by spaces.
+func (m symbolTransliterator) Transliterate(r rune) (string, bool) {
+	s, ok := m[r]
+	if !ok {
+		return "", false
+	}
+	return " " + s + " ", true
+}
@@ -0,0 +1,27 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var symbolTests = []struct {
+	input    string
+	symbols  map[rune]string
+	expected string
+}{
+	{"Salt & Pepper: 100% Organic™", nil, `salt-pepper-100-organic`},
+	{"Salt & Pepper: 100% Organic™", SymbolWords, `salt-and-pepper-100-percent-organic-tm`},
+	{"Tickets €25 or $30", SymbolWords, `tickets-euro-25-or-dollar-30`},
+	{"Tickets €25 or $30", SymbolCodes, `tickets-eur-25-or-usd-30`},
+	{"AT&T ©2024", SymbolCodes, `at-and-t-c-2024`},
+	{"C++ & C#", map[rune]string{'+': "p", '#': "sharp"}, `c-p-p-c-sharp`},
+}
+
+func TestSymbols(t *testing.T) {
+	for _, test := range symbolTests {
+		output := Slug(test.input, SlugOptions{Symbols: test.symbols})
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
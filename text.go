@@ -0,0 +1,222 @@
+package sanitize
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ControlChars removes C0 and C1 control characters including NUL, line and paragraph separators,
+// private use, surrogate and unassigned code points, and invalid UTF-8 from s.
+// Runes listed in keep, for example '\n' and '\t', are not removed.
+func ControlChars(s string, keep ...rune) string {
+	return strings.Map(func(r rune) rune {
+		for _, k := range keep {
+			if r == k {
+				return r
+			}
+		}
+		if r == unicode.ReplacementChar || !unicode.In(r, unicode.L, unicode.M, unicode.N, unicode.P, unicode.S, unicode.Zs, unicode.Cf) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Byte order marks for utf-8 and utf-16, removed by normalizeInput
+var byteOrderMarks = []string{"\xef\xbb\xbf", "\xfe\xff", "\xff\xfe"}
+
+// normalizeInput removes a byte order mark from the start of s and replaces \r\n and \r line endings with \n,
+// so that input is the same whichever platform it was submitted from.
+func normalizeInput(s string) string {
+	for _, bom := range byteOrderMarks {
+		s = strings.TrimPrefix(s, bom)
+	}
+	return NormalizeNewlines(s)
+}
+
+// cleanUTF8 removes NUL bytes and invalid utf-8 from s, or replaces them with U+FFFD if replace is true.
+// Each run of invalid bytes is replaced with a single U+FFFD.
+func cleanUTF8(s string, replace bool) string {
+	if utf8.ValidString(s) && strings.IndexByte(s, 0) == -1 {
+		return s
+	}
+	replacement := ""
+	if replace {
+		replacement = string(utf8.RuneError)
+	}
+	s = strings.ToValidUTF8(s, replacement)
+	return strings.Replace(s, "\x00", replacement, -1)
+}
+
+// isInvisible reports whether r is a character with no visible rendering which may be used to spoof text,
+// such as zero width spaces and joiners, bidi controls or tag characters.
+func isInvisible(r rune) bool {
+	switch {
+	case r == 0x00AD: // Soft hyphen
+		return true
+	case r == 0x034F: // Combining grapheme joiner
+		return true
+	case r == 0x061C: // Arabic letter mark
+		return true
+	case r == 0x115F || r == 0x1160 || r == 0x3164 || r == 0xFFA0: // Hangul fillers
+		return true
+	case r == 0x17B4 || r == 0x17B5: // Khmer inherent vowels
+		return true
+	case r == 0x180E: // Mongolian vowel separator
+		return true
+	case r >= 0x200B && r <= 0x200F: // Zero width space, non-joiner and joiner, left-to-right and right-to-left marks
+		return true
+	case r >= 0x202A && r <= 0x202E: // Bidi embeddings and overrides
+		return true
+	case r >= 0x2060 && r <= 0x2064: // Word joiner and invisible operators
+		return true
+	case r >= 0x2066 && r <= 0x2069: // Bidi isolates
+		return true
+	case r == 0xFEFF: // Zero width no-break space or byte order mark
+		return true
+	case r >= 0xE0000 && r <= 0xE007F: // Tag characters
+		return true
+	}
+	return false
+}
+
+// Invisible removes invisible characters which can be used to spoof names or hide code,
+// including zero width spaces and joiners, the word joiner, soft hyphens, byte order marks,
+// bidi control characters used in Trojan Source attacks, and unicode tag characters.
+// NB this removes the joiners used in emoji sequences like 👩‍💻.
+func Invisible(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isInvisible(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// CollapseWhitespace replaces each run of whitespace, including tabs, newlines and non-breaking spaces,
+// with a single space, and trims whitespace from either end of s.
+func CollapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// TrimLines trims whitespace from the end of each line in s, removing any blank lines
+// at the start or end of s. Indentation at the start of lines is kept.
+func TrimLines(s string) string {
+	lines := strings.Split(NormalizeNewlines(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Replace windows and old mac line endings with \n
+var newlines = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// NormalizeNewlines replaces \r\n and \r line endings in s with \n.
+func NormalizeNewlines(s string) string {
+	return newlines.Replace(s)
+}
+
+// Wrap wraps plain text at word boundaries so that lines are at most width columns wide,
+// for example 72 or 78 for plain text email. Existing line breaks are kept, and words longer
+// than width, such as urls, are placed on a line of their own rather than broken.
+func Wrap(s string, width int) string {
+	lines := strings.Split(NormalizeNewlines(s), "\n")
+	for i, line := range lines {
+		lines[i] = wrapLine(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLine wraps a single line of text, collapsing whitespace between words.
+func wrapLine(line string, width int) string {
+	b := strings.Builder{}
+	lineWidth := 0
+	for _, word := range strings.Fields(line) {
+		w := Width(word)
+		if lineWidth > 0 && lineWidth+1+w > width {
+			b.WriteString("\n")
+			lineWidth = 0
+		}
+		if lineWidth > 0 {
+			b.WriteString(" ")
+			lineWidth++
+		}
+		b.WriteString(word)
+		lineWidth += w
+	}
+	return b.String()
+}
+
+// Blank lines separate paragraphs in TextToHTML
+var paragraphBreaks = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// TextToHTML converts plain text to safe html, escaping the text, wrapping paragraphs separated
+// by blank lines in <p> and converting other line breaks to <br/>. It is the inverse of HTML.
+func TextToHTML(s string) string {
+	s = strings.TrimSpace(NormalizeNewlines(s))
+	if s == "" {
+		return ""
+	}
+
+	b := strings.Builder{}
+	for _, paragraph := range paragraphBreaks.Split(s, -1) {
+		b.WriteString("<p>")
+		b.WriteString(strings.Replace(template.HTMLEscapeString(paragraph), "\n", "<br/>", -1))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
+
+// keep returns s with only the runes for which f returns true.
+func keep(s string, f func(rune) bool) string {
+	return strings.Map(func(r rune) rune {
+		if f(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// isASCIIDigit reports whether r is 0-9.
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isASCIILetter reports whether r is a-z or A-Z.
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// Digits returns only the ascii digits 0-9 in s.
+func Digits(s string) string {
+	return keep(s, isASCIIDigit)
+}
+
+// Alpha returns only the ascii letters a-z and A-Z in s.
+func Alpha(s string) string {
+	return keep(s, isASCIILetter)
+}
+
+// AlphaNumeric returns only the ascii letters and digits in s.
+func AlphaNumeric(s string) string {
+	return keep(s, func(r rune) bool { return isASCIILetter(r) || isASCIIDigit(r) })
+}
+
+// DigitsUnicode returns only the decimal digits in s in any script, such as ٣ or ३.
+func DigitsUnicode(s string) string {
+	return keep(s, unicode.IsDigit)
+}
+
+// AlphaUnicode returns only the letters in s in any script, with their combining marks.
+func AlphaUnicode(s string) string {
+	return keep(s, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsMark(r) })
+}
+
+// AlphaNumericUnicode returns only the letters, with their combining marks, and decimal digits in s in any script.
+func AlphaNumericUnicode(s string) string {
+	return keep(s, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) })
+}
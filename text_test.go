@@ -0,0 +1,174 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var controlCharTests = []Test{
+	{"plain text", `plain text`},
+	{"null\x00byte", `nullbyte`},
+	{"bell\a and escape \x1b[31mred\x1b[0m", `bell and escape [31mred[0m`},
+	{"c1\u0085\u009bcontrols", `c1controls`},
+	{"line\nbreak\ttab\r", `linebreaktab`},
+	{"separators\u2028\u2029", `separators`},
+	{"private\ue000use", `privateuse`},
+	{"invalid\xff\xfeutf8", `invalidutf8`},
+	{"unassigned\U000E0FFF", `unassigned`},
+	{"accents é, emoji 🚀, nbsp\u00a0", "accents é, emoji 🚀, nbsp\u00a0"},
+}
+
+func TestControlChars(t *testing.T) {
+	for _, test := range controlCharTests {
+		output := ControlChars(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	output := ControlChars("keep\nnew\x00lines\r\n\tand tabs", '\n', '\t')
+	if output != "keep\nnewlines\n\tand tabs" {
+		t.Fatalf(Format, "keep\nnew\x00lines\r\n\tand tabs", "keep\nnewlines\n\tand tabs", output)
+	}
+}
+
+var invisibleTests = []Test{
+	{"admin", `admin`},
+	{"ad\u200bmin", `admin`},
+	{"ad\u200c\u200dmin\u2060", `admin`},
+	{"soft\u00adhyphen", `softhyphen`},
+	{"\ufeffbom", `bom`},
+	{"access_level = \"user\u202e \u2066// Check if admin\u2069 \u2066\"", `access_level = "user // Check if admin "`},
+	{"tagged\U000E0041\U000E007F", `tagged`},
+	{"\u3164", ``},
+	{"visible text, é and 🚀", `visible text, é and 🚀`},
+}
+
+func TestInvisible(t *testing.T) {
+	for _, test := range invisibleTests {
+		output := Invisible(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var collapseWhitespaceTests = []Test{
+	{"hello world", `hello world`},
+	{"  hello \t\n  world  ", `hello world`},
+	{"no\u00a0break\u2003em", `no break em`},
+	{"\n\r\t", ``},
+}
+
+func TestCollapseWhitespace(t *testing.T) {
+	for _, test := range collapseWhitespaceTests {
+		output := CollapseWhitespace(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var trimLinesTests = []Test{
+	{"one  \ntwo\t\n", "one\ntwo"},
+	{"\n\n  indented \r\n\r\n\nlast\n\n", "  indented\n\n\nlast"},
+	{"   \n\t\n", ``},
+}
+
+func TestTrimLines(t *testing.T) {
+	for _, test := range trimLinesTests {
+		output := TrimLines(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var normalizeNewlinesTests = []Test{
+	{"windows\r\nlines\r\n", "windows\nlines\n"},
+	{"mac\rlines\r", "mac\nlines\n"},
+	{"mixed\r\n\r\n\n\r", "mixed\n\n\n\n"},
+}
+
+func TestNormalizeNewlines(t *testing.T) {
+	for _, test := range normalizeNewlinesTests {
+		output := NormalizeNewlines(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var wrapTests = []struct {
+	input    string
+	width    int
+	expected string
+}{
+	{"short line", 72, "short line"},
+	{"the quick brown fox jumps over the lazy dog", 10, "the quick\nbrown fox\njumps over\nthe lazy\ndog"},
+	{"first paragraph\r\n\r\nsecond  paragraph", 10, "first\nparagraph\n\nsecond\nparagraph"},
+	{"see https://example.com/a/very/long/path?with=query for details", 20, "see\nhttps://example.com/a/very/long/path?with=query\nfor details"},
+	{"日本語 日本語 日本語", 13, "日本語 日本語\n日本語"},
+	{"", 72, ""},
+}
+
+func TestWrap(t *testing.T) {
+	for _, test := range wrapTests {
+		output := Wrap(test.input, test.width)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var textToHTMLTests = []Test{
+	{"hello world", `<p>hello world</p>`},
+	{"first\nline\n\nsecond paragraph", `<p>first<br/>line</p><p>second paragraph</p>`},
+	{"\r\n  one\r\n \r\n\r\ntwo  \n", `<p>one</p><p>two</p>`},
+	{"<script>alert('x') & \"y\"</script>", `<p>&lt;script&gt;alert(&#39;x&#39;) &amp; &#34;y&#34;&lt;/script&gt;</p>`},
+	{"  \n\n ", ``},
+}
+
+func TestTextToHTML(t *testing.T) {
+	for _, test := range textToHTMLTests {
+		output := TextToHTML(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var characterClassTests = []struct {
+	input               string
+	digits              string
+	alpha               string
+	alphaNumeric        string
+	digitsUnicode       string
+	alphaUnicode        string
+	alphaNumericUnicode string
+}{
+	{"abc-123 XYZ!", "123", "abcXYZ", "abc123XYZ", "123", "abcXYZ", "abc123XYZ"},
+	{"Café ٣२1", "1", "Caf", "Caf1", "٣२1", "Café", "Café٣२1"},
+	{"नमस्ते 42", "42", "", "42", "42", "नमस्ते", "नमस्ते42"},
+	{"½ ² <script>", "", "script", "script", "", "script", "script"},
+	{"", "", "", "", "", "", ""},
+}
+
+func TestCharacterClasses(t *testing.T) {
+	for _, test := range characterClassTests {
+		for _, c := range []struct {
+			f        func(string) string
+			expected string
+		}{
+			{Digits, test.digits},
+			{Alpha, test.alpha},
+			{AlphaNumeric, test.alphaNumeric},
+			{DigitsUnicode, test.digitsUnicode},
+			{AlphaUnicode, test.alphaUnicode},
+			{AlphaNumericUnicode, test.alphaNumericUnicode},
+		} {
+			if output := c.f(test.input); output != c.expected {
+				t.Fatalf(Format, test.input, c.expected, output)
+			}
+		}
+	}
+}
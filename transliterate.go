@@ -0,0 +1,225 @@
+package sanitize
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Transliterator converts a rune to ascii text, reporting false if it has no transliteration for the rune.
+type Transliterator interface {
+	Transliterate(r rune) (string, bool)
+}
+
+// TransliterationMap is a Transliterator using a map of runes to their transliterations.
+type TransliterationMap map[rune]string
+
+// Transliterate returns the transliteration of r from the map.
+func (m TransliterationMap) Transliterate(r rune) (string, bool) {
+	s, ok := m[r]
+	return s, ok
+}
+
+// Transliterators registered by applications, consulted before the built in table.
+var (
+	registeredMu              sync.RWMutex
+	registeredTransliterators []Transliterator
+)
+
+// RegisterTransliterator adds t to the transliterators consulted by Accents, Path and Name.
+// Transliterators registered later take precedence over those registered earlier,
+// and all take precedence over the built in transliterations, but not over those chosen for a call,
+// such as the language or cyrillic scheme set in SlugOptions.
+// It is safe to call concurrently, but is intended to be called during initialisation.
+func RegisterTransliterator(t Transliterator) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registeredTransliterators = append([]Transliterator{t}, registeredTransliterators...)
+}
+
+// RegisterTransliterations adds a table of transliterations consulted by Accents, Path and Name,
+// overriding any existing transliterations for the same runes.
+func RegisterTransliterations(m map[rune]string) {
+	table := make(TransliterationMap, len(m))
+	for r, s := range m {
+		table[r] = s
+	}
+	RegisterTransliterator(table)
+}
+
+// Tables for other scripts, consulted after the transliterations for latin letters.
+var scriptTables = []TransliterationMap{cyrillicPassport, greekLetters, arabicLetters, hebrewLetters}
+
+// transliterate returns the transliteration of c from the profiles given in order,
+// the registered transliterators, or the built in tables.
+func transliterate(c rune, profiles []Transliterator) (string, bool) {
+	for _, profile := range profiles {
+		if profile == nil {
+			continue
+		}
+		if s, ok := profile.Transliterate(c); ok {
+			return s, true
+		}
+	}
+
+	registeredMu.RLock()
+	for _, t := range registeredTransliterators {
+		if s, ok := t.Transliterate(c); ok {
+			registeredMu.RUnlock()
+			return s, true
+		}
+	}
+	registeredMu.RUnlock()
+
+	if s, ok := transliterations[c]; ok {
+		return s, true
+	}
+	for _, table := range scriptTables {
+		if s, ok := table[c]; ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// transliterateBase returns the transliteration of the base letter of c after removing combining marks,
+// for example the greek ά is transliterated as α.
+func transliterateBase(c rune, profiles []Transliterator) (string, bool) {
+	if c < utf8.RuneSelf {
+		return "", false
+	}
+
+	var base []rune
+	for _, r := range norm.NFD.String(string(c)) {
+		if !unicode.Is(unicode.Mn, r) {
+			base = append(base, r)
+		}
+	}
+	if len(base) != 1 || base[0] == c {
+		return "", false
+	}
+	return transliterate(base[0], profiles)
+}
+
+// flatten removes marks from any letters in a transliteration which decompose to ascii.
+func flatten(s string) string {
+	var b []rune
+	for _, c := range s {
+		if val, ok := decompose(c); ok {
+			b = append(b, []rune(val)...)
+		} else {
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// Letters with umlauts are written without the e used in german by most other languages.
+var plainUmlauts = TransliterationMap{
+	'Ä': "A",
+	'Ö': "O",
+	'Ü': "U",
+	'ä': "a",
+	'ö': "o",
+	'ü': "u",
+}
+
+// Swedish and Finnish also write å as a rather than the danish and norwegian aa.
+var swedishLetters = TransliterationMap{
+	'Ä': "A",
+	'Ö': "O",
+	'Ü': "U",
+	'Å': "A",
+	'ä': "a",
+	'ö': "o",
+	'ü': "u",
+	'å': "a",
+}
+
+// Transliteration profiles for languages whose conventions differ from the built in table,
+// which follows german, danish and norwegian conventions.
+var langProfiles = map[string]TransliterationMap{
+	"bg": bulgarianLetters,
+	"es": plainUmlauts,
+	"et": plainUmlauts,
+	"fi": swedishLetters,
+	"fr": plainUmlauts,
+	"hu": plainUmlauts,
+	"it": plainUmlauts,
+	"nl": plainUmlauts,
+	"pt": plainUmlauts,
+	"sk": plainUmlauts,
+	"sv": swedishLetters,
+	"tr": plainUmlauts,
+	"uk": ukrainianLetters,
+}
+
+// AccentsLang replaces accented characters with ascii equivalents like Accents,
+// following the conventions of the language given as a tag like "de" or "sv-SE".
+// For example ä becomes ae in german but a in swedish or finnish.
+func AccentsLang(s string, lang string) string {
+	return accents(s, langProfile(lang))
+}
+
+// langProfile returns the transliteration profile for the base language of a tag, or nil if there is none.
+func langProfile(lang string) TransliterationMap {
+	if lang == "" {
+		return nil
+	}
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return langProfiles[lang]
+}
+
+// decompose returns the ascii letters left after decomposing c (NFD) and removing combining marks,
+// for example č becomes c. This covers most accented latin letters, including every vietnamese vowel and tone,
+// while letters like đ which do not decompose are listed in the transliterations table.
+// It reports false if c is ascii or does not decompose to ascii.
+func decompose(c rune) (string, bool) {
+	if c < utf8.RuneSelf {
+		return "", false
+	}
+
+	var base []rune
+	for _, r := range norm.NFD.String(string(c)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if r >= utf8.RuneSelf {
+			return "", false
+		}
+		base = append(base, r)
+	}
+	if len(base) == 0 {
+		return "", false
+	}
+	return string(base), true
+}
+
+// withCapitals returns a table containing the entries in m, plus an entry for the capital of each letter
+// which transliterates to the capitalised transliteration, for example Щ becomes Shch.
+// Capitals which are ascii, like I for the turkish ı, are not added.
+func withCapitals(m map[rune]string) TransliterationMap {
+	table := make(TransliterationMap, len(m)*2)
+	for r, s := range m {
+		table[r] = s
+		upper := unicode.ToUpper(r)
+		if upper == r || upper < utf8.RuneSelf {
+			continue
+		}
+		if _, ok := m[upper]; ok {
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(s)
+		if size > 0 {
+			s = string(unicode.ToUpper(first)) + s[size:]
+		}
+		table[upper] = s
+	}
+	return table
+}
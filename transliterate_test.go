@@ -0,0 +1,152 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+type brandTransliterator struct{}
+
+func (brandTransliterator) Transliterate(r rune) (string, bool) {
+	if r == '™' {
+		return "tm", true
+	}
+	return "", false
+}
+
+func TestRegisterTransliterations(t *testing.T) {
+	defer func() { registeredTransliterators = nil }()
+
+	RegisterTransliterator(brandTransliterator{})
+	RegisterTransliterations(map[rune]string{'ö': "o", '☃': "snowman"})
+
+	tests := []Test{
+		{"Gophers™ Björk ☃", `Gopherstm Bjork snowman`},
+		{"Gophers™ Dörte", `Gopherstm Dorte`},
+	}
+	for _, test := range tests {
+		output := Accents(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	output := Path("Gophers™ Björk ☃")
+	if output != "gopherstm-bjork-snowman" {
+		t.Fatalf(Format, "Gophers™ Björk ☃", "gopherstm-bjork-snowman", output)
+	}
+
+	// Transliterations chosen for a call take precedence over registered transliterations
+	RegisterTransliterations(map[rune]string{'я': "ja", 'ä': "ae"})
+	slugTests := []struct {
+		input    string
+		opts     SlugOptions
+		expected string
+	}{
+		{"Мария Hämäläinen", SlugOptions{}, `marija-haemaelaeinen`},
+		{"Мария Hämäläinen", SlugOptions{Cyrillic: CyrillicBGN, Lang: "fi"}, `mariya-hamalainen`},
+	}
+	for _, test := range slugTests {
+		output := Slug(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+	if output := AccentsLang("Hämäläinen", "fi"); output != "Hamalainen" {
+		t.Fatalf(Format, "Hämäläinen", "Hamalainen", output)
+	}
+}
+
+var accentsLang = []struct {
+	input    string
+	lang     string
+	expected string
+}{
+	{"Jürgen Möller, Malmö", "de", `Juergen Moeller, Malmoe`},
+	{"Jürgen Möller, Malmö", "sv-SE", `Jurgen Moller, Malmo`},
+	{"Håkan Hämäläinen", "fi", `Hakan Hamalainen`},
+	{"Håkan Hämäläinen", "da", `Haakan Haemaelaeinen`},
+	{"pingüino", "es", `pinguino`},
+	{"Gödel", "", `Goedel`},
+}
+
+func TestAccentsLang(t *testing.T) {
+	for _, test := range accentsLang {
+		output := AccentsLang(test.input, test.lang)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	output := Slug("Malmö Möte", SlugOptions{Lang: "sv"})
+	if output != "malmo-mote" {
+		t.Fatalf(Format, "Malmö Möte", "malmo-mote", output)
+	}
+}
+
+// Every vietnamese vowel with each tone mark, in lower and upper case
+var vietnameseVowels = map[string]string{
+	"aàáảãạ": "a",
+	"ăằắẳẵặ": "a",
+	"âầấẩẫậ": "a",
+	"eèéẻẽẹ": "e",
+	"êềếểễệ": "e",
+	"iìíỉĩị": "i",
+	"oòóỏõọ": "o",
+	"ôồốổỗộ": "o",
+	"ơờớởỡợ": "o",
+	"uùúủũụ": "u",
+	"ưừứửữự": "u",
+	"yỳýỷỹỵ": "y",
+}
+
+func TestVietnamese(t *testing.T) {
+	for vowels, expected := range vietnameseVowels {
+		for _, v := range vowels {
+			output := Accents(string(v))
+			if output != expected {
+				t.Fatalf(Format, string(v), expected, output)
+			}
+			output = Accents(strings.ToUpper(string(v)))
+			if output != strings.ToUpper(expected) {
+				t.Fatalf(Format, strings.ToUpper(string(v)), strings.ToUpper(expected), output)
+			}
+		}
+	}
+
+	tests := []Test{
+		{"Đường phố Hà Nội", `duong-pho-ha-noi`},
+		{"Thành phố Hồ Chí Minh", `thanh-pho-ho-chi-minh`},
+		{"Tiếng Việt có dấu", `tieng-viet-co-dau`},
+		{"Phở bò đặc biệt", `pho-bo-dac-biet`},
+	}
+	for _, test := range tests {
+		output := Path(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+// Every letter in the transliteration tables should have its capital or lowercase counterpart
+func TestTransliterationCase(t *testing.T) {
+	tables := append([]TransliterationMap{transliterations}, scriptTables...)
+	tables = append(tables, cyrillicBGN, cyrillicScientific, ukrainianLetters, bulgarianLetters)
+	for _, table := range tables {
+		for r := range table {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			for _, other := range []rune{unicode.ToUpper(r), unicode.ToLower(r)} {
+				if other == r || other < utf8.RuneSelf {
+					continue
+				}
+				if _, ok := table[other]; !ok {
+					t.Fatalf("transliteration for %q has no entry for %q", r, other)
+				}
+			}
+		}
+	}
+}
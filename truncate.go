@@ -0,0 +1,130 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// graphemeLength returns the length in bytes of the grapheme cluster at the start of s.
+// This follows the main rules of UAX #29, keeping combining marks, emoji modifiers and
+// sequences joined by zero width joiners, flags, hangul syllables and \r\n together.
+func graphemeLength(s string) int {
+	if s == "" {
+		return 0
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if r == '\r' && strings.HasPrefix(s[size:], "\n") {
+		return size + 1
+	}
+	if unicode.IsControl(r) {
+		return size
+	}
+
+	regional := isRegionalIndicator(r)
+	prev := r
+	end := size
+	for end < len(s) {
+		next, nextSize := utf8.DecodeRuneInString(s[end:])
+		switch {
+		case unicode.Is(unicode.M, next) || isEmojiComponent(next):
+		case prev == 0x200D && isEmoji(next):
+		case regional && isRegionalIndicator(next):
+			regional = false
+		case isHangulJamo(prev) && isHangulJamo(next):
+		default:
+			return end
+		}
+		prev = next
+		end += nextSize
+	}
+	return end
+}
+
+// isHangulJamo reports whether r is a conjoining hangul jamo, which combine to form a single syllable.
+func isHangulJamo(r rune) bool {
+	return (r >= 0x1100 && r <= 0x11FF) || (r >= 0xA960 && r <= 0xA97F) || (r >= 0xD7B0 && r <= 0xD7FF)
+}
+
+// Length returns the number of grapheme clusters in s, the characters a reader would count,
+// so that a flag, an emoji with a skin tone or a letter with a combining accent each count as one.
+func Length(s string) int {
+	n := 0
+	for i := 0; i < len(s); i += graphemeLength(s[i:]) {
+		n++
+	}
+	return n
+}
+
+// Width returns the number of columns s occupies in a monospaced terminal. East asian wide
+// and full width characters and emoji take two columns, control and invisible characters none.
+func Width(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		n := graphemeLength(s[i:])
+		w += graphemeWidth(s[i : i+n])
+		i += n
+	}
+	return w
+}
+
+// graphemeWidth returns the number of columns used by a single grapheme cluster.
+func graphemeWidth(g string) int {
+	r, _ := utf8.DecodeRuneInString(g)
+	switch {
+	case unicode.IsControl(r) || isInvisible(r) || unicode.Is(unicode.M, r):
+		return 0
+	case r >= 0x1F000 || strings.ContainsRune(g, 0xFE0F) || strings.ContainsRune(g, 0x20E3):
+		return 2
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// Truncate shortens s to at most n grapheme clusters, including the ellipsis which is appended
+// if s is shortened. Multibyte characters, accented letters and emoji sequences such as flags are never split,
+// and trailing whitespace is removed before the ellipsis is added.
+func Truncate(s string, n int, ellipsis string) string {
+	if Length(s) <= n {
+		return s
+	}
+
+	// Leave room for the ellipsis, unless it is longer than the limit
+	if e := Length(ellipsis); e > n {
+		ellipsis = ""
+	} else {
+		n -= e
+	}
+
+	end := 0
+	for i := 0; i < n; i++ {
+		end += graphemeLength(s[end:])
+	}
+	return strings.TrimRightFunc(s[:end], unicode.IsSpace) + ellipsis
+}
+
+// TruncateWords shortens s to at most the number of words given, appending the ellipsis if s is shortened.
+// Words are separated by whitespace, which is kept as it was between the words remaining.
+func TruncateWords(s string, words int, ellipsis string) string {
+	count := 0
+	inWord := false
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			inWord = false
+			continue
+		}
+		if !inWord {
+			if count == words {
+				return strings.TrimRightFunc(s[:i], unicode.IsSpace) + ellipsis
+			}
+			count++
+			inWord = true
+		}
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var truncateTests = []struct {
+	input    string
+	n        int
+	ellipsis string
+	expected string
+}{
+	{"hello world", 20, "…", `hello world`},
+	{"hello world", 11, "…", `hello world`},
+	{"hello world", 8, "…", `hello w…`},
+	{"hello world", 8, "...", `hello...`},
+	{"hello world", 2, "...", `he`},
+	{"hello world", 0, "…", ``},
+	{"héllo wörld", 4, "", `héll`},
+	{"cafe\u0301 au lait", 4, "", "cafe\u0301"},
+	{"flags \U0001F1EC\U0001F1E7\U0001F1EB\U0001F1F7 here", 8, "", "flags \U0001F1EC\U0001F1E7\U0001F1EB\U0001F1F7"},
+	{"wave \U0001F44B\U0001F3FD hello", 7, "…", "wave \U0001F44B\U0001F3FD…"},
+	{"\U0001F469\u200d\U0001F4BB coder", 2, "", "\U0001F469\u200d\U0001F4BB"},
+	{"line\r\nbreak", 5, "", "line"},
+}
+
+func TestTruncate(t *testing.T) {
+	for _, test := range truncateTests {
+		output := Truncate(test.input, test.n, test.ellipsis)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var truncateWordsTests = []struct {
+	input    string
+	words    int
+	ellipsis string
+	expected string
+}{
+	{"the quick brown fox", 10, "…", `the quick brown fox`},
+	{"the quick brown fox", 4, "…", `the quick brown fox`},
+	{"the quick brown fox", 2, "…", `the quick…`},
+	{"  the  quick\n\nbrown fox ", 2, " [more]", `  the  quick [more]`},
+	{"the quick brown fox", 0, "…", `…`},
+	{"naïve café 🚀 launch", 3, "", `naïve café 🚀`},
+}
+
+func TestTruncateWords(t *testing.T) {
+	for _, test := range truncateWordsTests {
+		output := TruncateWords(test.input, test.words, test.ellipsis)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var lengthTests = []struct {
+	input  string
+	length int
+	width  int
+}{
+	{"hello", 5, 5},
+	{"", 0, 0},
+	{"café", 4, 4},
+	{"\U0001F1EC\U0001F1E7", 1, 2},
+	{"\U0001F44B\U0001F3FD", 1, 2},
+	{"\U0001F469\u200d\U0001F4BB", 1, 2},
+	{"日本語", 3, 6},
+	{"\uff21\uff22", 2, 4},
+	{"zero\u200bwidth", 10, 9},
+	{"❤\ufe0f", 1, 2},
+}
+
+func TestLength(t *testing.T) {
+	for _, test := range lengthTests {
+		if output := Length(test.input); output != test.length {
+			t.Fatalf("\ninput:    %q\nexpected: %d\noutput:   %d", test.input, test.length, output)
+		}
+		if output := Width(test.input); output != test.width {
+			t.Fatalf("\ninput:    %q\nexpected: %d\noutput:   %d", test.input, test.width, output)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package sanitize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TypographyOptions configures Typography. The zero value converts all typographic characters to ascii.
+type TypographyOptions struct {
+	// Smart converts ascii quotes, dashes and ellipses to typographic characters instead.
+	Smart bool
+
+	// KeepQuotes, KeepDashes, KeepEllipses and KeepSpaces leave those characters unchanged.
+	KeepQuotes   bool
+	KeepDashes   bool
+	KeepEllipses bool
+	KeepSpaces   bool
+}
+
+var (
+	typographicQuotes = strings.NewReplacer(
+		"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'", "‹", "'", "›", "'",
+		"“", "\"", "”", "\"", "„", "\"", "‟", "\"", "″", "\"", "«", "\"", "»", "\"",
+	)
+
+	typographicDashes = strings.NewReplacer(
+		"—", "--", "―", "--", "–", "-", "‒", "-", "−", "-", "‐", "-", "‑", "-",
+	)
+
+	typographicEllipses = strings.NewReplacer("…", "...")
+
+	smartDashes = strings.NewReplacer("---", "—", "--", "—", " - ", " – ")
+
+	smartEllipses = strings.NewReplacer("...", "…")
+)
+
+// Typography converts curly quotes, dashes, ellipses and non-breaking or other unusual spaces to ascii,
+// or with opts.Smart converts ascii quotes, dashes and ellipses to their typographic equivalents.
+func Typography(s string, opts TypographyOptions) string {
+	if opts.Smart {
+		return smartTypography(s, opts)
+	}
+	if !opts.KeepQuotes {
+		s = typographicQuotes.Replace(s)
+	}
+	if !opts.KeepDashes {
+		s = typographicDashes.Replace(s)
+	}
+	if !opts.KeepEllipses {
+		s = typographicEllipses.Replace(s)
+	}
+	if !opts.KeepSpaces {
+		s = strings.Map(func(r rune) rune {
+			if r != ' ' && unicode.Is(unicode.Zs, r) {
+				return ' '
+			}
+			return r
+		}, s)
+	}
+	return s
+}
+
+// smartTypography converts ascii punctuation in s to typographic characters.
+func smartTypography(s string, opts TypographyOptions) string {
+	if !opts.KeepEllipses {
+		s = smartEllipses.Replace(s)
+	}
+	if !opts.KeepDashes {
+		s = smartDashes.Replace(s)
+	}
+	if opts.KeepQuotes {
+		return s
+	}
+
+	b := strings.Builder{}
+	prev := ' '
+	for _, r := range s {
+		// Quotes are opening at the start of the text or after space or opening punctuation
+		opening := unicode.IsSpace(prev) || strings.ContainsRune("([{—–", prev)
+		switch {
+		case r == '"' && opening:
+			b.WriteRune('“')
+		case r == '"':
+			b.WriteRune('”')
+		case r == '\'' && opening:
+			b.WriteRune('‘')
+		case r == '\'':
+			b.WriteRune('’')
+		default:
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return b.String()
+}
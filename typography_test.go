@@ -0,0 +1,29 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var typographyTests = []struct {
+	input    string
+	opts     TypographyOptions
+	expected string
+}{
+	{"“Hello” — it’s ‘real’…", TypographyOptions{}, `"Hello" -- it's 'real'...`},
+	{"pages 10–12, «quoted» and ‹single›", TypographyOptions{}, `pages 10-12, "quoted" and 'single'`},
+	{"a b c　d", TypographyOptions{}, `a b c d`},
+	{"“Hello” — it’s…", TypographyOptions{KeepDashes: true, KeepEllipses: true}, `"Hello" — it's…`},
+	{"“Hello” — it’s…", TypographyOptions{KeepQuotes: true}, `“Hello” -- it’s...`},
+	{`"Hello" -- it's 'real'...`, TypographyOptions{Smart: true}, `“Hello” — it’s ‘real’…`},
+	{`pages 10 - 12 ("draft")`, TypographyOptions{Smart: true}, `pages 10 – 12 (“draft”)`},
+	{`"Hello"...`, TypographyOptions{Smart: true, KeepEllipses: true}, `“Hello”...`},
+}
+
+func TestTypography(t *testing.T) {
+	for _, test := range typographyTests {
+		output := Typography(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
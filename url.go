@@ -0,0 +1,194 @@
+package sanitize
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Errors returned by URL when a url cannot be made safe.
+var (
+	ErrURLInvalid = errors.New("sanitize: invalid url")
+	ErrURLScheme  = errors.New("sanitize: url scheme not allowed")
+	ErrURLHost    = errors.New("sanitize: invalid url host")
+
+	ErrURLConfusable = errors.New("sanitize: url host contains confusable characters")
+)
+
+var defaultSchemes = []string{"http", "https", "mailto"}
+
+// Query parameters used only to track visitors, removed if URLOptions.StripTracking is set.
+var trackingParams = []string{"fbclid", "gclid", "dclid", "msclkid", "yclid", "igshid", "mc_cid", "mc_eid", "_ga", "_hsenc", "_hsmi"}
+
+// URLOptions configures the checks and normalisation performed by URL.
+type URLOptions struct {
+	// Schemes lists the allowed schemes, if empty http, https and mailto are allowed.
+	Schemes []string
+
+	// AllowRelative allows urls without a scheme or host, such as /path or #anchor.
+	AllowRelative bool
+
+	// StripTracking removes tracking parameters such as utm_source or fbclid from the query.
+	StripTracking bool
+
+	// RejectConfusable rejects hosts which mix scripts or which spoof latin names, see ConfusableHost.
+	RejectConfusable bool
+}
+
+// URL makes a url safe to use in links and redirects.
+// The scheme is checked against the allowed schemes, scheme and host are lowercased,
+// the host is converted to punycode, the path is cleaned, userinfo is removed,
+// and the path, query and fragment are percent-encoded.
+func URL(s string, opts URLOptions) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", ErrURLInvalid
+	}
+
+	schemes := opts.Schemes
+	if len(schemes) == 0 {
+		schemes = defaultSchemes
+	}
+
+	// url.Parse lowercases the scheme for us
+	if u.Scheme == "" {
+		// Protocol relative urls like //example.com are not treated as relative
+		if !opts.AllowRelative || u.Host != "" || u.User != nil {
+			return "", ErrURLScheme
+		}
+	} else if !includes(schemes, u.Scheme) {
+		return "", ErrURLScheme
+	}
+
+	// Never pass on credentials
+	u.User = nil
+
+	if u.Host != "" || u.Scheme == "http" || u.Scheme == "https" {
+		if opts.RejectConfusable && ConfusableHost(u.Hostname()) {
+			return "", ErrURLConfusable
+		}
+		host, err := cleanHost(u.Hostname())
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		if port := u.Port(); port != "" {
+			host += ":" + port
+		}
+		u.Host = host
+	}
+
+	if u.Opaque == "" && u.Path != "" {
+		cleaned := cleanURLPath(u.EscapedPath())
+		u.Path, err = url.PathUnescape(cleaned)
+		if err != nil {
+			return "", ErrURLInvalid
+		}
+		u.RawPath = cleaned
+	}
+
+	if u.RawQuery != "" || u.ForceQuery {
+		u.RawQuery, err = cleanQuery(u.RawQuery, opts.StripTracking)
+		if err != nil {
+			return "", err
+		}
+		u.ForceQuery = false
+	}
+
+	return u.String(), nil
+}
+
+// cleanHost lowercases a host name and converts it to punycode.
+func cleanHost(host string) (string, error) {
+	if host == "" {
+		return "", ErrURLHost
+	}
+
+	// IPv6 addresses are left alone apart from case
+	if strings.Contains(host, ":") {
+		return strings.ToLower(host), nil
+	}
+
+	host, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", ErrURLHost
+	}
+	return strings.ToLower(host), nil
+}
+
+// cleanURLPath removes . and .. segments and repeated slashes from an escaped path, keeping any trailing slash.
+func cleanURLPath(p string) string {
+	cleaned := path.Clean(p)
+	if cleaned == "." {
+		return ""
+	}
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(cleaned, "/") {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// cleanQuery re-encodes each parameter in a raw query, preserving their order and optionally removing tracking parameters.
+func cleanQuery(raw string, stripTracking bool) (string, error) {
+	var params []string
+	for _, param := range strings.Split(raw, "&") {
+		if param == "" {
+			continue
+		}
+
+		key, value := param, ""
+		hasValue := false
+		if i := strings.Index(param, "="); i >= 0 {
+			key, value, hasValue = param[:i], param[i+1:], true
+		}
+
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return "", ErrURLInvalid
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return "", ErrURLInvalid
+		}
+
+		if stripTracking && isTrackingParam(key) {
+			continue
+		}
+
+		if hasValue {
+			params = append(params, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		} else {
+			params = append(params, url.QueryEscape(key))
+		}
+	}
+	return strings.Join(params, "&"), nil
+}
+
+// isTrackingParam reports whether a query parameter is used only for tracking.
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasPrefix(key, "utm_") || includes(trackingParams, key)
+}
+
+// Query returns a copy of values containing only the allowed parameters.
+// If valueSanitizer is not nil it is applied to every value kept.
+func Query(values url.Values, allowed []string, valueSanitizer func(string) string) url.Values {
+	cleaned := url.Values{}
+	for key, vals := range values {
+		if !includes(allowed, key) {
+			continue
+		}
+		for _, val := range vals {
+			if valueSanitizer != nil {
+				val = valueSanitizer(val)
+			}
+			cleaned.Add(key, val)
+		}
+	}
+	return cleaned
+}
@@ -0,0 +1,78 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ReservedUsernames is a list of names commonly reserved for staff or system accounts,
+// suitable for use as UsernameOptions.Reserved.
+var ReservedUsernames = []string{"abuse", "admin", "administrator", "anonymous", "help", "hostmaster", "info", "mod", "moderator", "noreply", "null", "postmaster", "root", "security", "staff", "support", "system", "webmaster"}
+
+// UsernameOptions configures Username.
+type UsernameOptions struct {
+	// MinLength is the minimum length of a username, shorter names are rejected.
+	MinLength int
+
+	// MaxLength is the maximum length of a username, longer names are truncated. Zero means no limit.
+	MaxLength int
+
+	// PreserveCase keeps the case of letters instead of lowercasing.
+	PreserveCase bool
+
+	// Reserved lists names which may not be used, for example ReservedUsernames.
+	// Names are compared ignoring case, both as transliterated and with confusable characters folded.
+	Reserved []string
+}
+
+// Only ascii letters, digits and - are allowed in usernames
+var illegalUsername = regexp.MustCompile(`[^[:alnum:]\-]`)
+
+// Username makes a string safe to use as a user handle, normalizing with NFKC, folding letters
+// which are confusable with latin letters in names which mix scripts (so that аdmin with a cyrillic а becomes admin),
+// transliterating accents and other scripts and removing all characters except ascii letters, digits and -.
+// Names written in a single script are transliterated, so Дмитрий becomes dmitrii.
+// If the name is too short or reserved an empty string is returned.
+func Username(s string, opts UsernameOptions) string {
+	name := Normalize(s, NFKC)
+
+	// Fold lookalike letters to latin in names which mix scripts, names in one script are transliterated
+	folded := foldConfusables(name)
+	if len(labelScripts(name)) > 1 {
+		name = folded
+	}
+
+	if !opts.PreserveCase {
+		name = strings.ToLower(name)
+	}
+
+	// Remove illegal characters, flattening accents and replacing separators with -
+	name = cleanString(name, illegalUsername)
+
+	// Names which look like a reserved name are also reserved, whatever their script
+	if includesFold(opts.Reserved, cleanString(strings.ToLower(folded), illegalUsername)) {
+		return ""
+	}
+
+	if opts.MaxLength > 0 && len(name) > opts.MaxLength {
+		name = strings.TrimRight(name[:opts.MaxLength], "-")
+	}
+
+	if len(name) < opts.MinLength || includesFold(opts.Reserved, name) {
+		return ""
+	}
+
+	return name
+}
+
+// foldConfusables replaces non-ascii letters which are confusable with latin letters by the latin letter,
+// leaving ascii digits and letters alone.
+func foldConfusables(s string) string {
+	return strings.Map(func(r rune) rune {
+		if prototype, ok := confusables[r]; ok && r > unicode.MaxASCII {
+			return prototype
+		}
+		return r
+	}, s)
+}
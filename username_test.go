@@ -0,0 +1,39 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var usernameTests = []struct {
+	input    string
+	opts     UsernameOptions
+	expected string
+}{
+	{"John Smith", UsernameOptions{}, `john-smith`},
+	{"  jane_doe99 ", UsernameOptions{}, `jane-doe99`},
+	{"Zoë", UsernameOptions{}, `zoe`},
+	{"Jörg", UsernameOptions{PreserveCase: true}, `Joerg`},
+	{"\uff2a\uff4f\uff48\uff4e", UsernameOptions{}, `john`},
+	{"аdmin", UsernameOptions{}, `admin`},
+	{"аdmin", UsernameOptions{Reserved: ReservedUsernames}, ``},
+	{"Дмитрий", UsernameOptions{}, `dmitrii`},
+	{"Χρήστος", UsernameOptions{}, `christos`},
+	{"Ѕуѕtеm", UsernameOptions{}, `system`},
+	{"ѕуѕтем", UsernameOptions{Reserved: ReservedUsernames}, ``},
+	{"Root", UsernameOptions{Reserved: ReservedUsernames}, ``},
+	{"rooted", UsernameOptions{Reserved: ReservedUsernames}, `rooted`},
+	{"l33t h4x0r", UsernameOptions{}, `l33t-h4x0r`},
+	{"ab", UsernameOptions{MinLength: 3}, ``},
+	{"a very long name indeed", UsernameOptions{MaxLength: 7}, `a-very`},
+	{"<script>alert(1)</script>", UsernameOptions{}, `scriptalert1script`},
+	{"@£$", UsernameOptions{}, ``},
+}
+
+func TestUsername(t *testing.T) {
+	for _, test := range usernameTests {
+		output := Username(test.input, test.opts)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package sanitize
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Digits and symbols commonly used in place of letters to avoid word filters
+var leetspeak = map[rune]rune{'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's', '!': 'i', '|': 'l'}
+
+// WordFilter censors banned words in text. Words are matched ignoring case, accents,
+// letters confusable with latin letters and common leetspeak substitutions, so that
+// a filter for bad also matches BAD, bäd, bаd with a cyrillic а, and b4d.
+// Only whole words are matched. A filter may be used with Policy.Text to censor html text content.
+type WordFilter struct {
+	// Replacement replaces each banned word, if empty each character of the word is replaced with *.
+	Replacement string
+
+	words map[string]bool
+}
+
+// NewWordFilter returns a filter for the words given.
+func NewWordFilter(words ...string) *WordFilter {
+	f := &WordFilter{words: make(map[string]bool)}
+	f.Add(words...)
+	return f
+}
+
+// Add adds words to the filter.
+func (f *WordFilter) Add(words ...string) {
+	if f.words == nil {
+		f.words = make(map[string]bool)
+	}
+	for _, w := range words {
+		if w = foldWord(strings.TrimSpace(w)); w != "" {
+			f.words[w] = true
+		}
+	}
+}
+
+// Load adds words from r to the filter, one per line, ignoring blank lines and lines starting with #.
+func (f *WordFilter) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			f.Add(line)
+		}
+	}
+	return scanner.Err()
+}
+
+// Match reports whether s contains a banned word.
+func (f *WordFilter) Match(s string) bool {
+	found := false
+	f.each(s, func(int, int) { found = true })
+	return found
+}
+
+// Replace replaces banned words in s with the filter replacement.
+func (f *WordFilter) Replace(s string) string {
+	b := strings.Builder{}
+	start := 0
+	f.each(s, func(i, j int) {
+		b.WriteString(s[start:i])
+		if f.Replacement != "" {
+			b.WriteString(f.Replacement)
+		} else {
+			b.WriteString(strings.Repeat("*", Length(s[i:j])))
+		}
+		start = j
+	})
+	b.WriteString(s[start:])
+	return b.String()
+}
+
+// each calls found with the start and end of each banned word in s.
+func (f *WordFilter) each(s string, found func(i, j int)) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !isWordRune(r) {
+			i += size
+			continue
+		}
+
+		// Find the end of the word
+		j := i + size
+		for j < len(s) {
+			r, size := utf8.DecodeRuneInString(s[j:])
+			if !isWordRune(r) {
+				break
+			}
+			j += size
+		}
+
+		// Symbols at either end of a word are punctuation, not letters, as in @name or bad!
+		start, end := i, j
+		for start < end && strings.ContainsRune("@!|", rune(s[start])) {
+			start++
+		}
+		for end > start && strings.ContainsRune("@!|", rune(s[end-1])) {
+			end--
+		}
+		if start < end && f.words[foldWord(s[start:end])] {
+			found(start, end)
+		}
+		i = j
+	}
+}
+
+// isWordRune reports whether r may be part of a word, including symbols used as letters.
+func isWordRune(r rune) bool {
+	_, leet := leetspeak[r]
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r) || leet
+}
+
+// foldWord returns a word in lowercase ascii where possible, with accents removed,
+// confusable letters replaced with their latin prototypes and leetspeak replaced with letters.
+func foldWord(w string) string {
+	w = strings.Map(func(r rune) rune {
+		if prototype, ok := confusables[r]; ok && r > unicode.MaxASCII {
+			return prototype
+		}
+		return r
+	}, norm.NFKD.String(w))
+	w = strings.ToLower(w)
+	return strings.Map(func(r rune) rune {
+		if letter, ok := leetspeak[r]; ok {
+			return letter
+		}
+		if unicode.Is(unicode.M, r) {
+			return -1
+		}
+		return r
+	}, w)
+}
@@ -0,0 +1,48 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+var wordFilterTests = []Test{
+	{"a clean sentence", `a clean sentence`},
+	{"this is bad", `this is ***`},
+	{"BAD and Bad!", `*** and ***!`},
+	{"b4d and b@d and 8ad", `*** and *** and 8ad`},
+	{"bäd or bаd", `*** or ***`},
+	{"badge and sinbad", `badge and sinbad`},
+	{"spam, spam.", `****, ****.`},
+	{"@bad", `@***`},
+}
+
+func TestWordFilter(t *testing.T) {
+	f := NewWordFilter("bad", "Spam")
+	for _, test := range wordFilterTests {
+		output := f.Replace(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	if f.Match("nothing here") || !f.Match("so BAD") {
+		t.Fatalf(Format, "so BAD", true, false)
+	}
+
+	// Words may be loaded from a list, with a placeholder replacement
+	f = &WordFilter{Replacement: "[removed]"}
+	if err := f.Load(strings.NewReader("# banned words\nheck\n\ndarn\n")); err != nil {
+		t.Fatal(err)
+	}
+	output := f.Replace("heck, darn it")
+	if output != "[removed], [removed] it" {
+		t.Fatalf(Format, "heck, darn it", "[removed], [removed] it", output)
+	}
+
+	// A filter may be used to censor html text content
+	p := &Policy{Tags: []string{"p"}, Text: NewWordFilter("bad").Replace}
+	output, _ = p.Sanitize(`<p class="bad">bad &amp; good</p>`)
+	if output != `<p>*** &amp; good</p>` {
+		t.Fatalf(Format, `<p class="bad">bad &amp; good</p>`, `<p>*** &amp; good</p>`, output)
+	}
+}
@@ -0,0 +1,143 @@
+package sanitize
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	parser "golang.org/x/net/html"
+)
+
+// ErrXMLDirective is returned by XMLAllowing for documents containing a DTD or other directive,
+// which might declare entities to be expanded by a later parser.
+var ErrXMLDirective = errors.New("sanitize: xml directives are not allowed")
+
+// The contents of these elements are removed along with the element, for example scripts in svg
+var xmlIgnoreTags = []string{"script", "style"}
+
+// Escapes for characters with special meaning in xml text and attributes
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+
+// XMLEscape escapes a string for use in xml text or attribute values, escaping &<>"'
+// and removing characters which are not allowed in xml 1.0, such as control characters
+// other than tab and line breaks.
+func XMLEscape(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if !legalXMLChar(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return xmlEscaper.Replace(s)
+}
+
+// legalXMLChar reports whether r is allowed by the Char production in xml 1.0.
+func legalXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// XMLAllowing sanitizes xml, allowing only the tags and attributes listed, for example for
+// user content in feeds or SOAP payloads. Unlike HTMLAllowing names are case sensitive and
+// may include a namespace prefix such as content:encoded, and text in CDATA sections is escaped.
+// Comments, processing instructions and script and style elements with their contents are removed.
+// Documents containing a DTD are rejected with ErrXMLDirective, so that no entities can be declared or expanded.
+func XMLAllowing(s string, tags []string, attributes []string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(cleanUTF8(s, false)))
+
+	// Named html entities such as &nbsp; are common in feeds, these are a fixed table and never expand to markup
+	decoder.Entity = xml.HTMLEntity
+
+	buffer := bytes.NewBufferString("")
+	var open []string
+	ignore := 0
+
+	for {
+		// RawToken keeps namespace prefixes as written, we check nesting ourselves
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+
+		case xml.StartElement:
+			name := xmlName(t.Name)
+			if ignore > 0 || includes(xmlIgnoreTags, name) {
+				ignore++
+				continue
+			}
+			open = append(open, name)
+			if includes(tags, name) {
+				buffer.WriteString("<" + name)
+				for _, attr := range xmlAttributes(t.Attr, attributes) {
+					buffer.WriteString(" " + attr.Key + `="` + XMLEscape(attr.Val) + `"`)
+				}
+				buffer.WriteString(">")
+			}
+
+		case xml.EndElement:
+			if ignore > 0 {
+				ignore--
+				continue
+			}
+			// Only close elements which were opened, closing any left open inside them
+			name := xmlName(t.Name)
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					closeXML(buffer, open[i:], tags)
+					open = open[:i]
+					break
+				}
+			}
+
+		case xml.CharData:
+			if ignore == 0 {
+				buffer.WriteString(XMLEscape(string(t)))
+			}
+
+		case xml.Directive:
+			return "", ErrXMLDirective
+
+		default:
+			// We ignore comments and processing instructions
+		}
+	}
+
+	closeXML(buffer, open, tags)
+	return buffer.String(), nil
+}
+
+// xmlName returns the name of an element or attribute with its namespace prefix, if any.
+func xmlName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// xmlAttributes returns the allowed attributes after removing malicious values, using the same rules as HTMLAllowing.
+func xmlAttributes(a []xml.Attr, allowed []string) []parser.Attribute {
+	attributes := make([]parser.Attribute, len(a))
+	for i, attr := range a {
+		attributes[i] = parser.Attribute{Key: xmlName(attr.Name), Val: attr.Value}
+	}
+	p := &Policy{Attributes: allowed, URLs: hrefOptions}
+	return p.cleanAttributes("", attributes, nil)
+}
+
+// closeXML writes end tags for the allowed elements in open, innermost first.
+func closeXML(buffer *bytes.Buffer, open []string, tags []string) {
+	for i := len(open) - 1; i >= 0; i-- {
+		if includes(tags, open[i]) {
+			buffer.WriteString("</" + open[i] + ">")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package sanitize
+
+import (
+	"testing"
+)
+
+var xmlEscapeTests = []Test{
+	{"plain text", `plain text`},
+	{`<a href="x">Tom & Jerry's</a>`, `&lt;a href=&quot;x&quot;&gt;Tom &amp; Jerry&apos;s&lt;/a&gt;`},
+	{"bell\a and null\x00", `bell and null`},
+	{"tab\tline\n", "tab\tline\n"},
+	{"café 🚀", `café 🚀`},
+}
+
+func TestXMLEscape(t *testing.T) {
+	for _, test := range xmlEscapeTests {
+		output := XMLEscape(test.input)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+}
+
+var xmlTestTags = []string{"item", "title", "link", "content:encoded", "svg", "a"}
+var xmlTestAttributes = []string{"href", "xml:lang"}
+
+var xmlAllowingTests = []Test{
+	{`<item><title>Hello</title></item>`, `<item><title>Hello</title></item>`},
+	{`<Item><Title>Case</Title></Item>`, `Case`},
+	{`<item xml:lang="en" onclick="evil()"><title>Attrs</title></item>`, `<item xml:lang="en"><title>Attrs</title></item>`},
+	{`<content:encoded><![CDATA[<p>Some <b>html</b></p>]]></content:encoded>`, `<content:encoded>&lt;p&gt;Some &lt;b&gt;html&lt;/b&gt;&lt;/p&gt;</content:encoded>`},
+	{`<?xml version="1.0"?><item><!-- comment --><title>Tom &amp; Jerry&nbsp;</title></item>`, "<item><title>Tom &amp; Jerry </title></item>"},
+	{`<svg><script>alert(1)</script><a href="javascript:alert(1)">x</a></svg>`, `<svg><a>x</a></svg>`},
+	{`<a href="http://a.com/" href="http://b.com/">dup</a>`, `<a href="http://a.com/">dup</a>`},
+	{`<item><unknown>kept text</unknown></item>`, `<item>kept text</item>`},
+	{`<item><title>unclosed`, `<item><title>unclosed</title></item>`},
+	{`<item><title>mismatched</item></title>`, `<item><title>mismatched</title></item>`},
+}
+
+func TestXMLAllowing(t *testing.T) {
+	for _, test := range xmlAllowingTests {
+		output, _ := XMLAllowing(test.input, xmlTestTags, xmlTestAttributes)
+		if output != test.expected {
+			t.Fatalf(Format, test.input, test.expected, output)
+		}
+	}
+
+	// Namespace declarations allowed by the caller are kept
+	input := `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/"><title>Feed</title></feed>`
+	expected := `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/"><title>Feed</title></feed>`
+	output, err := XMLAllowing(input, []string{"feed", "title"}, []string{"xmlns", "xmlns:media"})
+	if err != nil || output != expected {
+		t.Fatalf(Format, input, expected, output)
+	}
+
+	// Documents declaring entities are rejected
+	input = `<!DOCTYPE lolz [<!ENTITY lol "lol">]><item>&lol;</item>`
+	if _, err := XMLAllowing(input, xmlTestTags, xmlTestAttributes); err != ErrXMLDirective {
+		t.Fatalf(Format, input, ErrXMLDirective, err)
+	}
+}